@@ -0,0 +1,64 @@
+// Package attachments decides which files can be attached to authors and
+// books and where their contents are stored.
+package attachments
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/models"
+)
+
+var ErrUnsupportedType = errors.New("file type is not accepted for this resource")
+
+// SniffLen is how many leading bytes Detect looks at.
+const SniffLen = 512
+
+const epubType = "application/epub+zip"
+
+// extensions maps the accepted content types to the stored file's extension.
+var extensions = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/gif":       ".gif",
+	"image/webp":      ".webp",
+	"application/pdf": ".pdf",
+	epubType:          ".epub",
+}
+
+// accepted lists the content types each owner type may hold: portraits for
+// authors, sample chapters and illustrations for books.
+var accepted = map[string][]string{
+	models.AttachmentOwnerAuthor: {"image/jpeg", "image/png", "image/gif", "image/webp"},
+	models.AttachmentOwnerBook:   {"application/pdf", epubType, "image/jpeg", "image/png", "image/gif", "image/webp"},
+}
+
+// Detect returns the content type of a file from its first SniffLen bytes
+// and checks that ownerType accepts it. The type the client declared is
+// ignored.
+func Detect(ownerType string, head []byte) (string, error) {
+	contentType := http.DetectContentType(head)
+	if isEPUB(head) {
+		contentType = epubType
+	}
+	for _, t := range accepted[ownerType] {
+		if t == contentType {
+			return contentType, nil
+		}
+	}
+	return "", ErrUnsupportedType
+}
+
+// isEPUB recognises the EPUB container: a ZIP archive whose first entry is
+// an uncompressed file named "mimetype" holding the EPUB media type.
+func isEPUB(head []byte) bool {
+	return len(head) >= 58 && bytes.HasPrefix(head, []byte("PK\x03\x04")) &&
+		string(head[30:38]) == "mimetype" && string(head[38:58]) == epubType
+}
+
+// Key returns the storage key of content with the given SHA-256 checksum.
+// Keys depend only on the content, so identical uploads share one object.
+func Key(checksum, contentType string) string {
+	return "attachments/" + checksum[:2] + "/" + checksum + extensions[contentType]
+}
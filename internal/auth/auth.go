@@ -0,0 +1,55 @@
+// Package auth reads bearer tokens and checks the static ones that guard
+// operator endpoints such as moderation and webhook administration.
+package auth
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/tracing"
+	"github.com/gin-gonic/gin"
+)
+
+// BearerToken returns the token of the request's Authorization: Bearer
+// header.
+func BearerToken(c *gin.Context) (string, bool) {
+	scheme, token, ok := strings.Cut(c.GetHeader("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// HasToken reports whether the request's bearer token is one of tokens.
+// Tokens are compared in constant time.
+func HasToken(c *gin.Context, tokens []string) bool {
+	token, ok := BearerToken(c)
+	if !ok {
+		return false
+	}
+	for _, t := range tokens {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(t)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// Require answers 401 with missing to requests without a bearer token and
+// 403 with forbidden to those for which allowed returns false.
+func Require(allowed func(*gin.Context) bool, missing, forbidden string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := BearerToken(c); !ok {
+			c.Header("WWW-Authenticate", "Bearer")
+			c.AbortWithStatusJSON(http.StatusUnauthorized, tracing.ErrorBody(c, missing))
+			return
+		}
+		if !allowed(c) {
+			c.AbortWithStatusJSON(http.StatusForbidden, tracing.ErrorBody(c, forbidden))
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,103 @@
+// Package blob stores uploaded files behind the Store interface, on the
+// local filesystem or an S3-compatible server, and builds their public URLs.
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/config"
+)
+
+// ErrNotFound is returned by Open for a key that is not stored.
+var ErrNotFound = errors.New("blob not found")
+
+// keyPattern restricts keys to characters that need no escaping in paths
+// and URLs.
+var keyPattern = regexp.MustCompile(`^[A-Za-z0-9._-]+(/[A-Za-z0-9._-]+)*$`)
+
+// Info describes a stored object.
+type Info struct {
+	Size        int64
+	ContentType string
+	ModTime     time.Time
+}
+
+// Object is an open stored object. It can seek, so it can be served with
+// http.ServeContent, which answers range requests.
+type Object interface {
+	io.ReadSeekCloser
+	Info() Info
+}
+
+// Store keeps objects by key, a slash-separated relative path.
+type Store interface {
+	// Put stores size bytes read from r under key, replacing any object
+	// already there.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Open returns the object under key, or ErrNotFound.
+	Open(ctx context.Context, key string) (Object, error)
+	// Delete removes the object under key; a missing object is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// Default is the store configured by Init.
+var Default Store
+
+var publicURL string
+
+// Init creates Default from the configuration.
+func Init(cfg config.BlobConfig) error {
+	switch cfg.Backend {
+	case "local":
+		store, err := NewLocal(cfg.Dir)
+		if err != nil {
+			return err
+		}
+		Default = store
+	case "s3":
+		store, err := NewS3(S3Options{
+			Endpoint:  cfg.S3Endpoint,
+			Region:    cfg.S3Region,
+			Bucket:    cfg.S3Bucket,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+			PathStyle: cfg.S3PathStyle,
+		})
+		if err != nil {
+			return err
+		}
+		Default = store
+	default:
+		return fmt.Errorf("unknown blob backend %q", cfg.Backend)
+	}
+	publicURL = strings.TrimSuffix(cfg.PublicURL, "/")
+	return nil
+}
+
+// URL returns the public URL of key, or "" for an empty key.
+func URL(key string) string {
+	if key == "" {
+		return ""
+	}
+	return publicURL + "/" + key
+}
+
+// ValidKey reports whether key can be stored: a clean relative path of
+// letters, digits, dots, dashes and underscores.
+func ValidKey(key string) bool {
+	return keyPattern.MatchString(key) && path.Clean(key) == key && !strings.HasPrefix(key, "../") && key != ".." && key != "."
+}
+
+func checkKey(key string) error {
+	if !ValidKey(key) {
+		return fmt.Errorf("invalid blob key %q", key)
+	}
+	return nil
+}
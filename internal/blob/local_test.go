@@ -0,0 +1,77 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocalPutOpenDelete(t *testing.T) {
+	store, err := NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	if err := store.Put(ctx, "covers/1.txt", strings.NewReader("hello"), 5, ""); err != nil {
+		t.Fatal(err)
+	}
+	obj, err := store.Open(ctx, "covers/1.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(obj)
+	obj.Close()
+	if err != nil || string(data) != "hello" {
+		t.Fatalf("read %q, %v", data, err)
+	}
+	if info := obj.Info(); info.Size != 5 || !strings.HasPrefix(info.ContentType, "text/plain") {
+		t.Errorf("Info = %+v", info)
+	}
+
+	if err := store.Delete(ctx, "covers/1.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Open(ctx, "covers/1.txt"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Open after Delete = %v, want ErrNotFound", err)
+	}
+	if err := store.Delete(ctx, "covers/1.txt"); err != nil {
+		t.Errorf("Delete of a missing object = %v, want nil", err)
+	}
+}
+
+func TestLocalRejectsKeysOutsideRoot(t *testing.T) {
+	parent := t.TempDir()
+	root := filepath.Join(parent, "root")
+	store, err := NewLocal(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret := filepath.Join(parent, "secret.txt")
+	if err := os.WriteFile(secret, []byte("secret"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	for _, key := range []string{"../secret.txt", "a/../../secret.txt", "..", ".", "/etc/passwd", "a//b", "a/./b", ""} {
+		if err := store.Put(ctx, key, strings.NewReader("x"), 1, ""); err == nil {
+			t.Errorf("Put(%q) succeeded", key)
+		}
+		if _, err := store.Open(ctx, key); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Open(%q) = %v, want ErrNotFound", key, err)
+		}
+		if err := store.Delete(ctx, key); err == nil {
+			t.Errorf("Delete(%q) succeeded", key)
+		}
+	}
+	if _, err := os.Stat(secret); err != nil {
+		t.Errorf("file outside the root was touched: %v", err)
+	}
+	if _, err := os.Stat(root); err != nil {
+		t.Errorf("root was removed: %v", err)
+	}
+}
@@ -0,0 +1,41 @@
+// Package cache holds the shared Redis client used for rate limiting,
+// read-your-writes stickiness and idempotency keys.
+package cache
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/config"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/logging"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/tracing"
+	"github.com/go-redis/redis/v8"
+)
+
+var RDB *redis.Client
+
+// InitRedis creates the Redis client with tracing and logging hooks. An
+// unreachable server is logged, not fatal; the readiness probe reports it.
+func InitRedis(cfg config.RedisConfig) {
+	RDB = redis.NewClient(&redis.Options{
+		Addr:     fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
+		Password: cfg.Password,
+		DB:       cfg.DB,
+	})
+	RDB.AddHook(tracing.RedisHook{})
+	RDB.AddHook(logging.RedisHook{})
+	if err := RDB.Ping(context.Background()).Err(); err != nil {
+		slog.Warn("Redis ping failed", "error", err)
+	}
+}
+
+// Ping verifies the Redis server is reachable.
+func Ping(ctx context.Context) error {
+	return RDB.Ping(ctx).Err()
+}
+
+// Close closes the client's connection pool.
+func Close() error {
+	return RDB.Close()
+}
@@ -0,0 +1,422 @@
+// Package config loads the application configuration from defaults, an
+// optional YAML file, a .env file and the environment, in increasing order
+// of precedence.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the effective configuration of the server. Each field can be
+// set in the YAML file under its yaml key or through its env variable.
+type Config struct {
+	Server      ServerConfig      `yaml:"server"`
+	GRPC        GRPCConfig        `yaml:"grpc"`
+	Database    DatabaseConfig    `yaml:"database"`
+	Redis       RedisConfig       `yaml:"redis"`
+	RateLimit   RateLimitConfig   `yaml:"rate_limit"`
+	Health      HealthConfig      `yaml:"health"`
+	Shutdown    ShutdownConfig    `yaml:"shutdown"`
+	Log         LogConfig         `yaml:"log"`
+	Tracing     TracingConfig     `yaml:"tracing"`
+	Idempotency IdempotencyConfig `yaml:"idempotency"`
+	Webhooks    WebhooksConfig    `yaml:"webhooks"`
+	Outbox      OutboxConfig      `yaml:"outbox"`
+	Events      EventsConfig      `yaml:"events"`
+	Blob        BlobConfig        `yaml:"blob"`
+	Covers      CoversConfig      `yaml:"covers"`
+	Attachments AttachmentsConfig `yaml:"attachments"`
+	I18n        I18nConfig        `yaml:"i18n"`
+	Moderation  ModerationConfig  `yaml:"moderation"`
+}
+
+type ServerConfig struct {
+	Port              int           `yaml:"port" env:"PORT"`
+	ReadTimeout       time.Duration `yaml:"read_timeout" env:"HTTP_READ_TIMEOUT"`
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout" env:"HTTP_READ_HEADER_TIMEOUT"`
+	WriteTimeout      time.Duration `yaml:"write_timeout" env:"HTTP_WRITE_TIMEOUT"`
+	IdleTimeout       time.Duration `yaml:"idle_timeout" env:"HTTP_IDLE_TIMEOUT"`
+	TrustedProxies    []string      `yaml:"trusted_proxies" env:"TRUSTED_PROXIES"`
+
+	// RequestTimeout bounds the database and Redis work of each request;
+	// zero disables it.
+	RequestTimeout time.Duration `yaml:"request_timeout" env:"HTTP_REQUEST_TIMEOUT"`
+}
+
+type GRPCConfig struct {
+	Port int `yaml:"port" env:"GRPC_PORT"`
+}
+
+type DatabaseConfig struct {
+	Host     string `yaml:"host" env:"DB_HOST"`
+	Port     int    `yaml:"port" env:"DB_PORT"`
+	User     string `yaml:"user" env:"DB_USER"`
+	Password string `yaml:"password" env:"DB_PASSWORD" secret:"true"`
+	Name     string `yaml:"name" env:"DB_NAME"`
+
+	// TLS: SSLRootCert is the CA bundle used by verify-ca and verify-full;
+	// SSLCert and SSLKey enable client certificate authentication.
+	SSLMode     string `yaml:"sslmode" env:"DB_SSLMODE"`
+	SSLRootCert string `yaml:"sslrootcert" env:"DB_SSLROOTCERT"`
+	SSLCert     string `yaml:"sslcert" env:"DB_SSLCERT"`
+	SSLKey      string `yaml:"sslkey" env:"DB_SSLKEY"`
+
+	// Connection pool limits; zero lifetimes mean connections are reused forever.
+	MaxOpenConns    int           `yaml:"max_open_conns" env:"DB_MAX_OPEN_CONNS"`
+	MaxIdleConns    int           `yaml:"max_idle_conns" env:"DB_MAX_IDLE_CONNS"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" env:"DB_CONN_MAX_LIFETIME"`
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time" env:"DB_CONN_MAX_IDLE_TIME"`
+
+	// Read replicas serve GET requests. Clients that wrote within
+	// ReadYourWritesWindow read from the primary; unhealthy replicas are
+	// skipped until their next successful check.
+	ReplicaDSNs          []string      `yaml:"replica_dsns" env:"DB_REPLICA_DSNS" secret:"true"`
+	ReplicaCheckInterval time.Duration `yaml:"replica_check_interval" env:"DB_REPLICA_CHECK_INTERVAL"`
+	ReadYourWritesWindow time.Duration `yaml:"read_your_writes_window" env:"DB_READ_YOUR_WRITES_WINDOW"`
+
+	// Connection retries back off exponentially from RetryBackoff up to
+	// RetryMaxBackoff, with full jitter.
+	MaxRetries      int           `yaml:"max_retries" env:"DB_MAX_RETRIES"`
+	RetryBackoff    time.Duration `yaml:"retry_backoff" env:"DB_RETRY_BACKOFF"`
+	RetryMaxBackoff time.Duration `yaml:"retry_max_backoff" env:"DB_RETRY_MAX_BACKOFF"`
+}
+
+type RedisConfig struct {
+	Host     string `yaml:"host" env:"REDIS_HOST"`
+	Port     int    `yaml:"port" env:"REDIS_PORT"`
+	Password string `yaml:"password" env:"REDIS_PASSWORD" secret:"true"`
+	DB       int    `yaml:"db" env:"REDIS_DB"`
+}
+
+type RateLimitConfig struct {
+	Requests int           `yaml:"requests" env:"RATE_LIMIT_REQUESTS"`
+	Window   time.Duration `yaml:"window" env:"RATE_LIMIT_WINDOW"`
+}
+
+type HealthConfig struct {
+	CheckTimeout time.Duration `yaml:"check_timeout" env:"HEALTH_CHECK_TIMEOUT"`
+}
+
+type ShutdownConfig struct {
+	DrainPeriod time.Duration `yaml:"drain_period" env:"SHUTDOWN_DRAIN_PERIOD"`
+	Timeout     time.Duration `yaml:"timeout" env:"SHUTDOWN_TIMEOUT"`
+}
+
+// LogConfig selects the minimum level (debug, info, warn, error) and the
+// output format (json or text) of the structured logs.
+type LogConfig struct {
+	Level  string `yaml:"level" env:"LOG_LEVEL"`
+	Format string `yaml:"format" env:"LOG_FORMAT"`
+}
+
+// TracingConfig controls OpenTelemetry tracing. Spans are exported over
+// OTLP/gRPC to Endpoint, either host:port or a URL, and a SampleRatio of
+// root spans is kept; child spans follow their parent's decision.
+type TracingConfig struct {
+	Enabled     bool    `yaml:"enabled" env:"TRACING_ENABLED"`
+	Endpoint    string  `yaml:"endpoint" env:"OTEL_EXPORTER_OTLP_ENDPOINT"`
+	Insecure    bool    `yaml:"insecure" env:"OTEL_EXPORTER_OTLP_INSECURE"`
+	ServiceName string  `yaml:"service_name" env:"OTEL_SERVICE_NAME"`
+	SampleRatio float64 `yaml:"sample_ratio" env:"OTEL_TRACES_SAMPLER_ARG"`
+}
+
+// IdempotencyConfig controls Idempotency-Key handling: responses are kept
+// for TTL, and a key stays locked for at most LockTimeout while its first
+// request runs.
+type IdempotencyConfig struct {
+	TTL         time.Duration `yaml:"ttl" env:"IDEMPOTENCY_TTL"`
+	LockTimeout time.Duration `yaml:"lock_timeout" env:"IDEMPOTENCY_LOCK_TIMEOUT"`
+}
+
+// WebhooksConfig controls webhook deliveries. A failed delivery is retried
+// up to MaxAttempts times in total, waiting RetryBackoff after the first
+// failure and doubling up to RetryMaxBackoff. Subscriptions are managed
+// with one of AdminTokens, and deliveries only go to public addresses unless
+// AllowPrivateDestinations is set.
+type WebhooksConfig struct {
+	Timeout         time.Duration `yaml:"timeout" env:"WEBHOOK_TIMEOUT"`
+	MaxAttempts     int           `yaml:"max_attempts" env:"WEBHOOK_MAX_ATTEMPTS"`
+	RetryBackoff    time.Duration `yaml:"retry_backoff" env:"WEBHOOK_RETRY_BACKOFF"`
+	RetryMaxBackoff time.Duration `yaml:"retry_max_backoff" env:"WEBHOOK_RETRY_MAX_BACKOFF"`
+	PollInterval    time.Duration `yaml:"poll_interval" env:"WEBHOOK_POLL_INTERVAL"`
+
+	AdminTokens              []string `yaml:"admin_tokens" env:"WEBHOOK_ADMIN_TOKENS" secret:"true"`
+	AllowPrivateDestinations bool     `yaml:"allow_private_destinations" env:"WEBHOOK_ALLOW_PRIVATE_DESTINATIONS"`
+}
+
+// OutboxConfig controls the relay publishing outbox events to Redis Streams.
+// Events are spread over Partitions streams by aggregate, each created with
+// the consumer groups in Groups, and trimmed to about MaxLen entries (0
+// keeps everything). Published rows are deleted after Retention. Every
+// published event is also sent to the pub/sub Channel, if set.
+type OutboxConfig struct {
+	Stream       string        `yaml:"stream" env:"OUTBOX_STREAM"`
+	Channel      string        `yaml:"channel" env:"OUTBOX_CHANNEL"`
+	Partitions   int           `yaml:"partitions" env:"OUTBOX_PARTITIONS"`
+	Groups       []string      `yaml:"groups" env:"OUTBOX_CONSUMER_GROUPS"`
+	MaxLen       int           `yaml:"max_len" env:"OUTBOX_STREAM_MAXLEN"`
+	BatchSize    int           `yaml:"batch_size" env:"OUTBOX_BATCH_SIZE"`
+	PollInterval time.Duration `yaml:"poll_interval" env:"OUTBOX_POLL_INTERVAL"`
+	Retention    time.Duration `yaml:"retention" env:"OUTBOX_RETENTION"`
+}
+
+// EventsConfig controls the Server-Sent Events endpoints. A comment is sent
+// every Heartbeat to keep idle connections open, and a client resuming with
+// Last-Event-ID is sent at most Backlog missed events.
+type EventsConfig struct {
+	Heartbeat time.Duration `yaml:"heartbeat" env:"EVENTS_HEARTBEAT"`
+	Backlog   int           `yaml:"backlog" env:"EVENTS_BACKLOG"`
+}
+
+// BlobConfig selects where uploaded files are stored: "local" keeps them
+// under Dir, "s3" in Bucket on an S3-compatible server. Stored objects are
+// linked as PublicURL followed by their key; the default serves them from
+// the API's own /media route.
+type BlobConfig struct {
+	Backend   string `yaml:"backend" env:"BLOB_BACKEND"`
+	Dir       string `yaml:"dir" env:"BLOB_DIR"`
+	PublicURL string `yaml:"public_url" env:"BLOB_PUBLIC_URL"`
+
+	// S3-compatible storage such as AWS S3 or MinIO. Path-style addressing
+	// (endpoint/bucket/key) suits most self-hosted servers.
+	S3Endpoint  string `yaml:"s3_endpoint" env:"S3_ENDPOINT"`
+	S3Region    string `yaml:"s3_region" env:"S3_REGION"`
+	S3Bucket    string `yaml:"s3_bucket" env:"S3_BUCKET"`
+	S3AccessKey string `yaml:"s3_access_key_id" env:"S3_ACCESS_KEY_ID"`
+	S3SecretKey string `yaml:"s3_secret_access_key" env:"S3_SECRET_ACCESS_KEY" secret:"true"`
+	S3PathStyle bool   `yaml:"s3_force_path_style" env:"S3_FORCE_PATH_STYLE"`
+}
+
+// CoversConfig limits book cover uploads.
+type CoversConfig struct {
+	MaxBytes int `yaml:"max_bytes" env:"COVER_MAX_BYTES"`
+}
+
+// AttachmentsConfig limits author and book attachments: each file may be up
+// to MaxBytes, each author or book may hold OwnerQuota bytes, and all
+// stored files together TotalQuota bytes (0 for no limit), counting files
+// shared through de-duplication once. Uploads and downloads may take up to
+// TransferTimeout.
+type AttachmentsConfig struct {
+	MaxBytes        int           `yaml:"max_bytes" env:"ATTACHMENT_MAX_BYTES"`
+	OwnerQuota      int           `yaml:"owner_quota" env:"ATTACHMENT_OWNER_QUOTA"`
+	TotalQuota      int           `yaml:"total_quota" env:"ATTACHMENT_TOTAL_QUOTA"`
+	TransferTimeout time.Duration `yaml:"transfer_timeout" env:"ATTACHMENT_TRANSFER_TIMEOUT"`
+}
+
+// I18nConfig sets DefaultLanguage, the BCP 47 tag of the language the
+// catalog's own titles, descriptions and biographies are written in.
+// Translations into other languages are served to clients that prefer them
+// through Accept-Language.
+type I18nConfig struct {
+	DefaultLanguage string `yaml:"default_language" env:"I18N_DEFAULT_LANGUAGE"`
+}
+
+// ModerationConfig sets how new reviews are screened. Reviews containing a
+// BlockedWords entry are rejected; those containing a FlaggedWords entry,
+// more than MaxLinks links, or the same text as a review posted within
+// DuplicateWindow (0 to disable) are held for a moderator, as are all of
+// them with RequireApproval. Requests carrying one of ModeratorTokens as a
+// bearer token may use the moderation endpoints.
+type ModerationConfig struct {
+	RequireApproval bool          `yaml:"require_approval" env:"MODERATION_REQUIRE_APPROVAL"`
+	BlockedWords    []string      `yaml:"blocked_words" env:"MODERATION_BLOCKED_WORDS"`
+	FlaggedWords    []string      `yaml:"flagged_words" env:"MODERATION_FLAGGED_WORDS"`
+	MaxLinks        int           `yaml:"max_links" env:"MODERATION_MAX_LINKS"`
+	DuplicateWindow time.Duration `yaml:"duplicate_window" env:"MODERATION_DUPLICATE_WINDOW"`
+	ModeratorTokens []string      `yaml:"moderator_tokens" env:"MODERATOR_TOKENS" secret:"true"`
+}
+
+// Default returns the configuration used for anything not set elsewhere.
+func Default() Config {
+	return Config{
+		Server: ServerConfig{
+			Port:              8080,
+			ReadTimeout:       15 * time.Second,
+			ReadHeaderTimeout: 5 * time.Second,
+			WriteTimeout:      30 * time.Second,
+			IdleTimeout:       60 * time.Second,
+			RequestTimeout:    10 * time.Second,
+			TrustedProxies:    []string{"127.0.0.1"},
+		},
+		GRPC: GRPCConfig{Port: 50051},
+		Database: DatabaseConfig{
+			Port:            5432,
+			SSLMode:         "disable",
+			MaxOpenConns:    25,
+			MaxIdleConns:    10,
+			ConnMaxLifetime: 30 * time.Minute,
+			ConnMaxIdleTime: 5 * time.Minute,
+			MaxRetries:      5,
+			RetryBackoff:    time.Second,
+			RetryMaxBackoff: 30 * time.Second,
+
+			ReplicaCheckInterval: 10 * time.Second,
+			ReadYourWritesWindow: 5 * time.Second,
+		},
+		Redis: RedisConfig{Port: 6379},
+		// 5 istek / 1 dakika
+		RateLimit: RateLimitConfig{Requests: 5, Window: time.Minute},
+		Health:    HealthConfig{CheckTimeout: 2 * time.Second},
+		Shutdown:  ShutdownConfig{DrainPeriod: 5 * time.Second, Timeout: 20 * time.Second},
+		Log:       LogConfig{Level: "info", Format: "json"},
+		Tracing: TracingConfig{
+			Endpoint:    "localhost:4317",
+			Insecure:    true,
+			ServiceName: "book-library-api",
+			SampleRatio: 1,
+		},
+		Idempotency: IdempotencyConfig{TTL: 24 * time.Hour, LockTimeout: 30 * time.Second},
+		Webhooks: WebhooksConfig{
+			Timeout:         10 * time.Second,
+			MaxAttempts:     8,
+			RetryBackoff:    10 * time.Second,
+			RetryMaxBackoff: time.Hour,
+			PollInterval:    time.Second,
+		},
+		Outbox: OutboxConfig{
+			Stream:       "catalog.events",
+			Channel:      "catalog.events.live",
+			Partitions:   1,
+			MaxLen:       100000,
+			BatchSize:    100,
+			PollInterval: 500 * time.Millisecond,
+			Retention:    7 * 24 * time.Hour,
+		},
+		Events: EventsConfig{Heartbeat: 15 * time.Second, Backlog: 1000},
+		Blob: BlobConfig{
+			Backend:     "local",
+			Dir:         "./data/blobs",
+			PublicURL:   "/media",
+			S3Region:    "us-east-1",
+			S3PathStyle: true,
+		},
+		Covers: CoversConfig{MaxBytes: 5 << 20},
+		Attachments: AttachmentsConfig{
+			MaxBytes:        20 << 20,
+			OwnerQuota:      100 << 20,
+			TotalQuota:      10 << 30,
+			TransferTimeout: 10 * time.Minute,
+		},
+		I18n:       I18nConfig{DefaultLanguage: "en"},
+		Moderation: ModerationConfig{MaxLinks: 1, DuplicateWindow: 24 * time.Hour},
+	}
+}
+
+// DefaultFile is read when CONFIG_FILE is not set, if it exists.
+const DefaultFile = "config.yaml"
+
+// Load builds the configuration from the defaults, the YAML file named by
+// CONFIG_FILE (or config.yaml if present), .env and the environment. It does
+// not validate the result; call Validate for that.
+func Load() (Config, error) {
+	cfg := Default()
+
+	// .env never overrides variables already set in the environment.
+	if err := godotenv.Load(); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return cfg, fmt.Errorf("loading .env: %w", err)
+	}
+
+	path, explicit := os.LookupEnv("CONFIG_FILE")
+	if !explicit {
+		path = DefaultFile
+	}
+	if data, err := os.ReadFile(path); err == nil {
+		if err := yaml.Unmarshal(data, &cfg); err != nil {
+			return cfg, fmt.Errorf("parsing %s: %w", path, err)
+		}
+	} else if explicit || !errors.Is(err, os.ErrNotExist) {
+		return cfg, fmt.Errorf("reading config file: %w", err)
+	}
+
+	if err := applyEnv(reflect.ValueOf(&cfg).Elem()); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// listSeparator returns ";" for list values that contain one, so items such
+// as key=value DSNs can themselves contain commas; otherwise ",".
+func listSeparator(raw string) string {
+	if strings.Contains(raw, ";") {
+		return ";"
+	}
+	return ","
+}
+
+// applyEnv overrides every field that has an env tag and a non-empty
+// environment variable.
+func applyEnv(v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field, sf := v.Field(i), t.Field(i)
+		if sf.Type.Kind() == reflect.Struct {
+			if err := applyEnv(field); err != nil {
+				return err
+			}
+			continue
+		}
+		key := sf.Tag.Get("env")
+		raw, ok := os.LookupEnv(key)
+		if key == "" || !ok || raw == "" {
+			continue
+		}
+		if err := setField(field, raw); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+func setField(field reflect.Value, raw string) error {
+	switch {
+	case field.Type() == durationType:
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(d))
+	case field.Kind() == reflect.String:
+		field.SetString(raw)
+	case field.Kind() == reflect.Int:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", raw)
+		}
+		field.SetInt(int64(n))
+	case field.Kind() == reflect.Float64:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", raw)
+		}
+		field.SetFloat(f)
+	case field.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", raw)
+		}
+		field.SetBool(b)
+	case field.Kind() == reflect.Slice && field.Type().Elem().Kind() == reflect.String:
+		var items []string
+		for _, item := range strings.Split(raw, listSeparator(raw)) {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		field.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported field type %s", field.Type())
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"io"
+	"reflect"
+
+	"gopkg.in/yaml.v3"
+)
+
+const redacted = "REDACTED"
+
+// Redacted returns a copy of the configuration with every field tagged
+// secret:"true" masked, so it can be logged or printed.
+func (c Config) Redacted() Config {
+	out := c
+	redact(reflect.ValueOf(&out).Elem())
+	return out
+}
+
+func redact(v reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field, sf := v.Field(i), t.Field(i)
+		if sf.Type.Kind() == reflect.Struct {
+			redact(field)
+			continue
+		}
+		if sf.Tag.Get("secret") != "true" {
+			continue
+		}
+		switch {
+		case field.Kind() == reflect.String && field.String() != "":
+			field.SetString(redacted)
+		case field.Kind() == reflect.Slice && field.Type().Elem().Kind() == reflect.String:
+			masked := make([]string, field.Len())
+			for j := range masked {
+				masked[j] = redacted
+			}
+			field.Set(reflect.ValueOf(masked))
+		}
+	}
+}
+
+// Print writes the redacted configuration as YAML.
+func (c Config) Print(w io.Writer) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(c.Redacted()); err != nil {
+		return err
+	}
+	return enc.Close()
+}
@@ -0,0 +1,144 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// Validate reports every problem with the configuration at once, naming
+// the environment variable to fix.
+func (c Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(validPort(c.Server.Port), "PORT must be between 1 and 65535, got %d", c.Server.Port)
+	check(validPort(c.GRPC.Port), "GRPC_PORT must be between 1 and 65535, got %d", c.GRPC.Port)
+	check(c.Server.Port != c.GRPC.Port, "PORT and GRPC_PORT must differ, both are %d", c.Server.Port)
+	check(c.Server.ReadTimeout > 0, "HTTP_READ_TIMEOUT must be positive")
+	check(c.Server.ReadHeaderTimeout > 0, "HTTP_READ_HEADER_TIMEOUT must be positive")
+	check(c.Server.WriteTimeout > 0, "HTTP_WRITE_TIMEOUT must be positive")
+	check(c.Server.IdleTimeout > 0, "HTTP_IDLE_TIMEOUT must be positive")
+	check(c.Server.RequestTimeout >= 0, "HTTP_REQUEST_TIMEOUT must not be negative")
+	check(c.Server.RequestTimeout < c.Server.WriteTimeout,
+		"HTTP_REQUEST_TIMEOUT (%s) must be shorter than HTTP_WRITE_TIMEOUT (%s)", c.Server.RequestTimeout, c.Server.WriteTimeout)
+	for _, proxy := range c.Server.TrustedProxies {
+		_, _, cidrErr := net.ParseCIDR(proxy)
+		check(net.ParseIP(proxy) != nil || cidrErr == nil, "TRUSTED_PROXIES entry %q is not an IP or CIDR", proxy)
+	}
+
+	check(c.Database.Host != "", "DB_HOST is required")
+	check(validPort(c.Database.Port), "DB_PORT must be between 1 and 65535, got %d", c.Database.Port)
+	check(c.Database.User != "", "DB_USER is required")
+	check(c.Database.Password != "", "DB_PASSWORD is required")
+	check(c.Database.Name != "", "DB_NAME is required")
+	check(sslModes[c.Database.SSLMode], "DB_SSLMODE %q is not a valid PostgreSQL sslmode", c.Database.SSLMode)
+	if c.Database.SSLMode == "verify-ca" || c.Database.SSLMode == "verify-full" {
+		check(c.Database.SSLRootCert != "", "DB_SSLROOTCERT is required when DB_SSLMODE is %s", c.Database.SSLMode)
+	}
+	check((c.Database.SSLCert == "") == (c.Database.SSLKey == ""), "DB_SSLCERT and DB_SSLKEY must be set together")
+	for env, path := range map[string]string{
+		"DB_SSLROOTCERT": c.Database.SSLRootCert,
+		"DB_SSLCERT":     c.Database.SSLCert,
+		"DB_SSLKEY":      c.Database.SSLKey,
+	} {
+		if path != "" {
+			_, err := os.Stat(path)
+			check(err == nil, "%s: %v", env, err)
+		}
+	}
+	check(c.Database.MaxOpenConns >= 0, "DB_MAX_OPEN_CONNS must not be negative")
+	check(c.Database.MaxIdleConns >= 0, "DB_MAX_IDLE_CONNS must not be negative")
+	check(c.Database.MaxOpenConns == 0 || c.Database.MaxIdleConns <= c.Database.MaxOpenConns,
+		"DB_MAX_IDLE_CONNS (%d) must not exceed DB_MAX_OPEN_CONNS (%d)", c.Database.MaxIdleConns, c.Database.MaxOpenConns)
+	check(c.Database.ConnMaxLifetime >= 0, "DB_CONN_MAX_LIFETIME must not be negative")
+	check(c.Database.ConnMaxIdleTime >= 0, "DB_CONN_MAX_IDLE_TIME must not be negative")
+	if len(c.Database.ReplicaDSNs) > 0 {
+		check(c.Database.ReplicaCheckInterval > 0, "DB_REPLICA_CHECK_INTERVAL must be positive")
+		check(c.Database.ReadYourWritesWindow >= 0, "DB_READ_YOUR_WRITES_WINDOW must not be negative")
+	}
+	check(c.Database.MaxRetries >= 1, "DB_MAX_RETRIES must be at least 1")
+	check(c.Database.RetryBackoff > 0, "DB_RETRY_BACKOFF must be positive")
+	check(c.Database.RetryMaxBackoff >= c.Database.RetryBackoff, "DB_RETRY_MAX_BACKOFF must be at least DB_RETRY_BACKOFF")
+
+	check(c.Redis.Host != "", "REDIS_HOST is required")
+	check(validPort(c.Redis.Port), "REDIS_PORT must be between 1 and 65535, got %d", c.Redis.Port)
+	check(c.Redis.DB >= 0, "REDIS_DB must not be negative")
+
+	check(c.RateLimit.Requests > 0, "RATE_LIMIT_REQUESTS must be positive")
+	check(c.RateLimit.Window > 0, "RATE_LIMIT_WINDOW must be positive")
+	check(c.Health.CheckTimeout > 0, "HEALTH_CHECK_TIMEOUT must be positive")
+	check(c.Shutdown.DrainPeriod >= 0, "SHUTDOWN_DRAIN_PERIOD must not be negative")
+	check(c.Shutdown.Timeout > 0, "SHUTDOWN_TIMEOUT must be positive")
+	check(c.Idempotency.TTL > 0, "IDEMPOTENCY_TTL must be positive")
+	check(c.Idempotency.LockTimeout > 0, "IDEMPOTENCY_LOCK_TIMEOUT must be positive")
+	check(c.Webhooks.Timeout > 0, "WEBHOOK_TIMEOUT must be positive")
+	check(c.Webhooks.MaxAttempts >= 1, "WEBHOOK_MAX_ATTEMPTS must be at least 1")
+	check(c.Webhooks.RetryBackoff > 0, "WEBHOOK_RETRY_BACKOFF must be positive")
+	check(c.Webhooks.RetryMaxBackoff >= c.Webhooks.RetryBackoff, "WEBHOOK_RETRY_MAX_BACKOFF must be at least WEBHOOK_RETRY_BACKOFF")
+	check(c.Webhooks.PollInterval > 0, "WEBHOOK_POLL_INTERVAL must be positive")
+	check(c.Outbox.Stream != "", "OUTBOX_STREAM is required")
+	check(c.Outbox.Partitions >= 1, "OUTBOX_PARTITIONS must be at least 1, got %d", c.Outbox.Partitions)
+	check(c.Outbox.MaxLen >= 0, "OUTBOX_STREAM_MAXLEN must not be negative, got %d", c.Outbox.MaxLen)
+	check(c.Outbox.BatchSize >= 1, "OUTBOX_BATCH_SIZE must be at least 1, got %d", c.Outbox.BatchSize)
+	check(c.Outbox.PollInterval > 0, "OUTBOX_POLL_INTERVAL must be positive")
+	check(c.Outbox.Retention > 0, "OUTBOX_RETENTION must be positive")
+	check(c.Events.Heartbeat > 0, "EVENTS_HEARTBEAT must be positive")
+	check(c.Events.Backlog >= 0, "EVENTS_BACKLOG must not be negative, got %d", c.Events.Backlog)
+	switch c.Blob.Backend {
+	case "local":
+		check(c.Blob.Dir != "", "BLOB_DIR is required when BLOB_BACKEND is local")
+	case "s3":
+		check(c.Blob.S3Endpoint != "", "S3_ENDPOINT is required when BLOB_BACKEND is s3")
+		check(c.Blob.S3Bucket != "", "S3_BUCKET is required when BLOB_BACKEND is s3")
+		check(c.Blob.S3Region != "", "S3_REGION is required when BLOB_BACKEND is s3")
+		check(c.Blob.S3AccessKey != "" && c.Blob.S3SecretKey != "", "S3_ACCESS_KEY_ID and S3_SECRET_ACCESS_KEY are required when BLOB_BACKEND is s3")
+	default:
+		check(false, "BLOB_BACKEND %q must be local or s3", c.Blob.Backend)
+	}
+	check(c.Covers.MaxBytes > 0, "COVER_MAX_BYTES must be positive, got %d", c.Covers.MaxBytes)
+	check(c.Attachments.MaxBytes > 0, "ATTACHMENT_MAX_BYTES must be positive, got %d", c.Attachments.MaxBytes)
+	check(c.Attachments.OwnerQuota >= c.Attachments.MaxBytes, "ATTACHMENT_OWNER_QUOTA must be at least ATTACHMENT_MAX_BYTES")
+	check(c.Attachments.TotalQuota >= 0, "ATTACHMENT_TOTAL_QUOTA must not be negative, got %d", c.Attachments.TotalQuota)
+	check(c.Attachments.TransferTimeout > 0, "ATTACHMENT_TRANSFER_TIMEOUT must be positive")
+	check(c.Moderation.MaxLinks >= 0, "MODERATION_MAX_LINKS must not be negative, got %d", c.Moderation.MaxLinks)
+	check(c.Moderation.DuplicateWindow >= 0, "MODERATION_DUPLICATE_WINDOW must not be negative")
+	if tag, err := language.Parse(c.I18n.DefaultLanguage); err != nil || tag == language.Und {
+		check(false, "I18N_DEFAULT_LANGUAGE %q must be a BCP 47 language tag", c.I18n.DefaultLanguage)
+	}
+	check(logLevels[strings.ToLower(c.Log.Level)], "LOG_LEVEL %q must be one of debug, info, warn, error", c.Log.Level)
+	check(logFormats[strings.ToLower(c.Log.Format)], "LOG_FORMAT %q must be json or text", c.Log.Format)
+	if c.Tracing.Enabled {
+		check(c.Tracing.Endpoint != "", "OTEL_EXPORTER_OTLP_ENDPOINT is required when TRACING_ENABLED is true")
+		check(c.Tracing.ServiceName != "", "OTEL_SERVICE_NAME is required when TRACING_ENABLED is true")
+		check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1,
+			"OTEL_TRACES_SAMPLER_ARG must be between 0 and 1, got %g", c.Tracing.SampleRatio)
+	}
+
+	return errors.Join(errs...)
+}
+
+var sslModes = map[string]bool{
+	"disable":     true,
+	"allow":       true,
+	"prefer":      true,
+	"require":     true,
+	"verify-ca":   true,
+	"verify-full": true,
+}
+
+var logLevels = map[string]bool{"debug": true, "info": true, "warn": true, "error": true}
+
+var logFormats = map[string]bool{"json": true, "text": true}
+
+func validPort(p int) bool {
+	return p > 0 && p <= 65535
+}
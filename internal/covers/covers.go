@@ -0,0 +1,151 @@
+// Package covers validates uploaded book cover images and renders their
+// thumbnails.
+package covers
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif" // registers the GIF decoder
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"path"
+	"strings"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // registers the WebP decoder
+)
+
+var (
+	ErrUnsupportedType = errors.New("cover must be a JPEG, PNG, GIF or WebP image")
+	ErrInvalidImage    = errors.New("cover image could not be decoded")
+	ErrTooManyPixels   = fmt.Errorf("cover image must not exceed %d pixels", maxPixels)
+)
+
+// maxPixels bounds the decoded size of an upload, so a small file cannot
+// expand into a huge bitmap.
+const maxPixels = 40_000_000
+
+// Size is a thumbnail rendered for every cover, at most Width pixels wide.
+type Size struct {
+	Name  string
+	Width int
+}
+
+// Sizes lists the thumbnails generated for every cover.
+var Sizes = []Size{
+	{Name: "small", Width: 150},
+	{Name: "medium", Width: 400},
+}
+
+// extensions maps the accepted content types to the original's file extension.
+var extensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// File is an image ready to be stored.
+type File struct {
+	Key         string
+	ContentType string
+	Data        []byte
+}
+
+// Process checks that data is a supported image and returns it along with
+// its thumbnails, keyed under a new directory for the book. The content
+// type is detected from the data, not taken from the client.
+func Process(bookID uint, data []byte) ([]File, error) {
+	contentType := http.DetectContentType(data)
+	ext, ok := extensions[contentType]
+	if !ok {
+		return nil, ErrUnsupportedType
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+	if cfg.Width*cfg.Height > maxPixels {
+		return nil, ErrTooManyPixels
+	}
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+
+	dir := fmt.Sprintf("covers/%d/%s", bookID, newID())
+	original := dir + "/original" + ext
+	files := []File{{Key: original, ContentType: contentType, Data: data}}
+	for _, size := range Sizes {
+		thumb, err := render(src, size.Width, thumbnailType(original))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, File{Key: ThumbnailKey(original, size.Name), ContentType: thumbnailType(original), Data: thumb})
+	}
+	return files, nil
+}
+
+// Keys returns the keys of the original stored under key and its thumbnails.
+func Keys(key string) []string {
+	keys := []string{key}
+	for _, size := range Sizes {
+		keys = append(keys, ThumbnailKey(key, size.Name))
+	}
+	return keys
+}
+
+// ThumbnailKey returns the key of the named thumbnail of the original
+// stored under key. PNG covers keep PNG thumbnails to preserve
+// transparency; all others get JPEG ones.
+func ThumbnailKey(key, size string) string {
+	ext := ".jpg"
+	if thumbnailType(key) == "image/png" {
+		ext = ".png"
+	}
+	return path.Dir(key) + "/" + size + ext
+}
+
+func thumbnailType(key string) string {
+	if strings.HasSuffix(key, ".png") {
+		return "image/png"
+	}
+	return "image/jpeg"
+}
+
+// render scales src down to width, keeping its aspect ratio, and encodes
+// it. Images already narrower are only re-encoded.
+func render(src image.Image, width int, contentType string) ([]byte, error) {
+	bounds := src.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if w > width {
+		h = max(1, h*width/w)
+		w = width
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	if contentType == "image/jpeg" {
+		// JPEG has no transparency; flatten onto white instead of black
+		draw.Draw(dst, dst.Bounds(), image.White, image.Point{}, draw.Src)
+	}
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, bounds, draw.Over, nil)
+
+	var buf bytes.Buffer
+	var err error
+	if contentType == "image/png" {
+		err = png.Encode(&buf, dst)
+	} else {
+		err = jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 85})
+	}
+	return buf.Bytes(), err
+}
+
+func newID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -1,55 +1,160 @@
 package db
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"log"
-	"os"
+	"log/slog"
+	"math/rand/v2"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/config"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/logging"
 	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/models"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/tracing"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
 var DB *gorm.DB
 
-func InitDB() {
-	maxRetries := 5
+// InitDB connects to PostgreSQL, retrying up to cfg.MaxRetries times with
+// exponential backoff, configures the connection pool and migrates the schema.
+func InitDB(cfg config.DatabaseConfig) {
 	var err error
 
-	host := os.Getenv("DB_HOST")
-	user := os.Getenv("DB_USER")
-	password := os.Getenv("DB_PASSWORD")
-	dbname := os.Getenv("DB_NAME")
-	port := os.Getenv("DB_PORT")
-
-	if host == "" || user == "" || password == "" || dbname == "" || port == "" {
-		log.Fatal("Database connection environment variables are not set correctly.")
-	}
-
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable",
-		host, user, password, dbname, port)
-
-	for i := 0; i < maxRetries; i++ {
-		DB, err = gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	for i := 0; i < cfg.MaxRetries; i++ {
+		DB, err = gorm.Open(postgres.Open(dsn(cfg)), &gorm.Config{Logger: logging.NewGormLogger()})
 		if err == nil {
 			break
 		}
-		log.Printf("Database connection attempt %d/%d failed: %v", i+1, maxRetries, err)
-		time.Sleep(5 * time.Second)
+		slog.Warn("Database connection attempt failed", "attempt", i+1, "max_attempts", cfg.MaxRetries, "error", err)
+		if i < cfg.MaxRetries-1 {
+			time.Sleep(backoff(i, cfg.RetryBackoff, cfg.RetryMaxBackoff))
+		}
 	}
 
 	if err != nil {
-		log.Fatal("Failed to connect to database after retries:", err)
+		logging.Fatal("Failed to connect to database after retries", "error", err)
+	}
+	if err := DB.Use(tracing.GormPlugin{}); err != nil {
+		logging.Fatal("Failed to register tracing plugin", "error", err)
 	}
 
-	err = DB.AutoMigrate(&models.Author{}, &models.Book{}, &models.Review{})
+	sqlDB, err := DB.DB()
 	if err != nil {
-		log.Fatalf("Failed to migrate database: %v", err)
+		logging.Fatal("Failed to access database pool", "error", err)
+	}
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+
+	err = DB.AutoMigrate(&models.Author{}, &models.Genre{}, &models.Series{}, &models.Book{}, &models.BookContributor{},
+		&models.Publisher{}, &models.Edition{}, &models.Review{},
+		&models.WebhookSubscription{}, &models.WebhookDelivery{}, &models.OutboxEvent{}, &models.Attachment{},
+		&models.BookTranslation{}, &models.AuthorTranslation{})
+	if err != nil {
+		logging.Fatal("Failed to migrate database", "error", err)
 	}
 
 	addForeignKey("books", "author_id", "authors(id)", "CASCADE")
 	addForeignKey("reviews", "book_id", "books(id)", "CASCADE")
+	addForeignKey("book_contributors", "book_id", "books(id)", "CASCADE")
+	addForeignKey("books", "series_id", "series(id)", "SET NULL")
+	addForeignKey("editions", "book_id", "books(id)", "CASCADE")
+	addForeignKey("editions", "publisher_id", "publishers(id)", "SET NULL")
+	addForeignKey("book_translations", "book_id", "books(id)", "CASCADE")
+	addForeignKey("author_translations", "author_id", "authors(id)", "CASCADE")
+	addForeignKey("webhook_deliveries", "subscription_id", "webhook_subscriptions(id)", "CASCADE")
+
+	if err := backfillContributors(); err != nil {
+		logging.Fatal("Failed to migrate book authors to contributors", "error", err)
+	}
+	if err := backfillEditions(); err != nil {
+		logging.Fatal("Failed to migrate book ISBNs to editions", "error", err)
+	}
+	if err := scrubDeliveryErrors(); err != nil {
+		logging.Fatal("Failed to remove response bodies from the webhook delivery log", "error", err)
+	}
+}
+
+// scrubDeliveryErrors removes the response bodies that failed webhook
+// deliveries used to record, keeping only their status.
+func scrubDeliveryErrors() error {
+	return DB.Exec(`
+        UPDATE webhook_deliveries SET last_error = 'HTTP ' || response_status
+        WHERE response_status <> 0 AND last_error <> 'HTTP ' || response_status`).Error
+}
+
+// backfillEditions gives every book without editions, i.e. books created
+// before editions existed, an edition with its ISBN.
+func backfillEditions() error {
+	result := DB.Exec(`
+        INSERT INTO editions (book_id, isbn, format, page_count, language)
+        SELECT id, isbn, '', 0, '' FROM books
+        WHERE NOT EXISTS (SELECT 1 FROM editions WHERE editions.book_id = books.id)`)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		slog.Info("Migrated book ISBNs to editions", "books", result.RowsAffected)
+	}
+	return nil
+}
+
+// backfillContributors credits the author of every book that has no
+// contributors yet, i.e. books created before contributors existed.
+func backfillContributors() error {
+	result := DB.Exec(`
+        INSERT INTO book_contributors (book_id, author_id, role, position)
+        SELECT id, author_id, ?, 0 FROM books
+        WHERE NOT EXISTS (SELECT 1 FROM book_contributors WHERE book_contributors.book_id = books.id)`,
+		models.RoleAuthor)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		slog.Info("Migrated book authors to contributors", "books", result.RowsAffected)
+	}
+	return nil
+}
+
+// dsn builds a libpq-style connection string, quoting every value so
+// passwords and paths may contain spaces or quotes.
+func dsn(cfg config.DatabaseConfig) string {
+	params := [][2]string{
+		{"host", cfg.Host},
+		{"port", strconv.Itoa(cfg.Port)},
+		{"user", cfg.User},
+		{"password", cfg.Password},
+		{"dbname", cfg.Name},
+		{"sslmode", cfg.SSLMode},
+		{"sslrootcert", cfg.SSLRootCert},
+		{"sslcert", cfg.SSLCert},
+		{"sslkey", cfg.SSLKey},
+	}
+	quoter := strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+	var parts []string
+	for _, p := range params {
+		if p[1] != "" {
+			parts = append(parts, fmt.Sprintf("%s='%s'", p[0], quoter.Replace(p[1])))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// backoff returns the delay before retry attempt+1: a random duration up
+// to base*2^attempt, capped at max ("full jitter"), so restarting replicas
+// do not retry in lockstep.
+func backoff(attempt int, base, max time.Duration) time.Duration {
+	ceiling := max
+	if attempt < 32 && base<<attempt > 0 && base<<attempt < max {
+		ceiling = base << attempt
+	}
+	return rand.N(ceiling) + 1
 }
 
 func addForeignKey(table, field, ref, onDelete string) {
@@ -63,7 +168,7 @@ func addForeignKey(table, field, ref, onDelete string) {
 		table, table, field)).Scan(&count).Error
 
 	if err != nil {
-		log.Printf("Warning: Could not check foreign key constraint for %s: %v", table, err)
+		slog.Warn("Could not check foreign key constraint", "table", table, "error", err)
 		return
 	}
 
@@ -77,9 +182,29 @@ func addForeignKey(table, field, ref, onDelete string) {
 			table, table, field, field, ref, onDelete)).Error
 
 		if err != nil {
-			log.Printf("Warning: Could not add foreign key constraint to %s: %v", table, err)
+			slog.Warn("Could not add foreign key constraint", "table", table, "error", err)
 		}
 	} else {
-		log.Printf("Foreign key constraint fk_%s_%s already exists on table %s", table, field, table)
+		slog.Debug("Foreign key constraint already exists", "table", table, "constraint", "fk_"+table+"_"+field)
+	}
+}
+
+// Close closes the primary and replica connection pools, waiting for
+// in-use connections to be returned.
+func Close() error {
+	replicaErr := closeReplicas()
+	sqlDB, err := DB.DB()
+	if err != nil {
+		return err
+	}
+	return errors.Join(sqlDB.Close(), replicaErr)
+}
+
+// Ping verifies a connection to the database is still alive.
+func Ping(ctx context.Context) error {
+	sqlDB, err := DB.DB()
+	if err != nil {
+		return err
 	}
+	return sqlDB.PingContext(ctx)
 }
@@ -0,0 +1,171 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/config"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/logging"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/tracing"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// replica is a read-only connection pool whose health is checked in the
+// background.
+type replica struct {
+	name    string
+	db      *gorm.DB
+	healthy atomic.Bool
+}
+
+var (
+	replicas     []*replica
+	nextReplica  atomic.Uint64
+	stopReplicas = make(chan struct{})
+	replicasDone sync.WaitGroup
+)
+
+type primaryKey struct{}
+
+// WithPrimary pins every Reader call made with the returned context to the
+// primary, e.g. right after the client wrote and replicas may lag behind.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+func pinnedToPrimary(ctx context.Context) bool {
+	pinned, _ := ctx.Value(primaryKey{}).(bool)
+	return pinned
+}
+
+// Reader returns a session for read-only queries. It picks a healthy
+// replica round-robin, falling back to the primary when the context is
+// pinned to it or no replica is healthy. Inside Transaction it returns the
+// transaction.
+func Reader(ctx context.Context) *gorm.DB {
+	if tx, ok := txFrom(ctx); ok {
+		return tx.WithContext(ctx)
+	}
+	if len(replicas) > 0 && !pinnedToPrimary(ctx) {
+		start := nextReplica.Add(1)
+		for i := range replicas {
+			r := replicas[(start+uint64(i))%uint64(len(replicas))]
+			if r.healthy.Load() {
+				return r.db.WithContext(ctx)
+			}
+		}
+	}
+	return DB.WithContext(ctx)
+}
+
+// Writer returns a session on the primary for mutations and for reads that
+// must see them, or the transaction when called inside Transaction.
+func Writer(ctx context.Context) *gorm.DB {
+	if tx, ok := txFrom(ctx); ok {
+		return tx.WithContext(ctx)
+	}
+	return DB.WithContext(ctx)
+}
+
+// HasReplicas reports whether any read replica is configured.
+func HasReplicas() bool {
+	return len(replicas) > 0
+}
+
+// InitReplicas opens a pool per replica DSN, using the same pool limits as
+// the primary, and starts checking their health every cfg.ReplicaCheckInterval.
+// A replica that cannot be reached at startup is kept and marked unhealthy.
+func InitReplicas(cfg config.DatabaseConfig) {
+	for i, replicaDSN := range cfg.ReplicaDSNs {
+		// Not pinged here: an unreachable replica is kept, its first check
+		// marks it unhealthy and later ones bring it back.
+		conn, err := gorm.Open(postgres.Open(replicaDSN), &gorm.Config{Logger: logging.NewGormLogger(), DisableAutomaticPing: true})
+		if err != nil {
+			slog.Warn("Could not open read replica", "replica", i+1, "error", err)
+			continue
+		}
+		if err := conn.Use(tracing.GormPlugin{}); err != nil {
+			slog.Warn("Could not register tracing plugin on read replica", "replica", i+1, "error", err)
+		}
+		sqlDB, err := conn.DB()
+		if err != nil {
+			slog.Warn("Could not access read replica pool", "replica", i+1, "error", err)
+			continue
+		}
+		sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+		sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+		sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+		sqlDB.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+
+		r := &replica{name: fmt.Sprintf("replica-%d", i+1), db: conn}
+		// Assumed healthy so the first check logs a replica that is down
+		r.healthy.Store(true)
+		r.check(cfg.ReplicaCheckInterval)
+		replicas = append(replicas, r)
+	}
+	if len(replicas) == 0 {
+		return
+	}
+
+	replicasDone.Add(1)
+	go func() {
+		defer replicasDone.Done()
+		ticker := time.NewTicker(cfg.ReplicaCheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				for _, r := range replicas {
+					r.check(cfg.ReplicaCheckInterval)
+				}
+			case <-stopReplicas:
+				return
+			}
+		}
+	}()
+}
+
+// check pings the replica and logs health transitions.
+func (r *replica) check(timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var err error
+	if sqlDB, dbErr := r.db.DB(); dbErr != nil {
+		err = dbErr
+	} else {
+		err = sqlDB.PingContext(ctx)
+	}
+
+	healthy := err == nil
+	if r.healthy.Swap(healthy) != healthy {
+		if healthy {
+			slog.Info("Read replica is healthy", "replica", r.name)
+		} else {
+			slog.Warn("Read replica is unhealthy, reads fall back to the primary", "replica", r.name, "error", err)
+		}
+	}
+}
+
+// closeReplicas stops the health checks and closes every replica pool.
+func closeReplicas() error {
+	if len(replicas) == 0 {
+		return nil
+	}
+	close(stopReplicas)
+	replicasDone.Wait()
+
+	var errs []error
+	for _, r := range replicas {
+		if sqlDB, err := r.db.DB(); err == nil {
+			errs = append(errs, sqlDB.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
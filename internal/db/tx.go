@@ -0,0 +1,61 @@
+package db
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type txKey struct{}
+
+// txState is the transaction carried by a context and the functions to run
+// once it commits.
+type txState struct {
+	tx          *gorm.DB
+	afterCommit []func(ctx context.Context)
+}
+
+// Transaction runs fn in a transaction on the primary. Reader and Writer
+// calls made with the context passed to fn use the transaction, so service
+// functions compose into one all-or-nothing unit. The transaction is rolled
+// back if fn returns an error or panics. If ctx already carries a
+// transaction, fn joins it and the outer caller decides whether it commits.
+func Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := txFrom(ctx); ok {
+		return fn(ctx)
+	}
+	state := &txState{}
+	err := DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		state.tx = tx
+		return fn(context.WithValue(ctx, txKey{}, state))
+	})
+	if err != nil {
+		return err
+	}
+	for _, f := range state.afterCommit {
+		f(ctx)
+	}
+	return nil
+}
+
+// AfterCommit runs fn once the transaction carried by ctx has committed, or
+// right away if there is none. It is dropped if the transaction rolls back,
+// so side effects outside the database, like deleting files, only happen
+// for changes that were kept. fn gets the context the transaction was
+// started with, so it can start transactions of its own.
+func AfterCommit(ctx context.Context, fn func(ctx context.Context)) {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		state.afterCommit = append(state.afterCommit, fn)
+		return
+	}
+	fn(ctx)
+}
+
+// txFrom returns the transaction carried by ctx, if any.
+func txFrom(ctx context.Context) (*gorm.DB, bool) {
+	state, ok := ctx.Value(txKey{}).(*txState)
+	if !ok {
+		return nil, false
+	}
+	return state.tx, true
+}
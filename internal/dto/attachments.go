@@ -0,0 +1,46 @@
+package dto
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/models"
+)
+
+// AttachmentResponse is the public representation of an attachment. URL
+// downloads its contents.
+type AttachmentResponse struct {
+	ID          uint      `json:"id"`
+	OwnerType   string    `json:"owner_type"`
+	OwnerID     uint      `json:"owner_id"`
+	Name        string    `json:"name"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	Checksum    string    `json:"checksum"`
+	URL         string    `json:"url"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// NewAttachmentResponse maps an attachment to its response.
+func NewAttachmentResponse(a models.Attachment) AttachmentResponse {
+	return AttachmentResponse{
+		ID:          a.ID,
+		OwnerType:   a.OwnerType,
+		OwnerID:     a.OwnerID,
+		Name:        a.Name,
+		ContentType: a.ContentType,
+		Size:        a.Size,
+		Checksum:    a.Checksum,
+		URL:         "/api/v1/attachments/" + strconv.FormatUint(uint64(a.ID), 10) + "/download",
+		CreatedAt:   a.CreatedAt,
+	}
+}
+
+// NewAttachmentResponses maps a slice of attachments to their responses.
+func NewAttachmentResponses(atts []models.Attachment) []AttachmentResponse {
+	resp := make([]AttachmentResponse, 0, len(atts))
+	for _, att := range atts {
+		resp = append(resp, NewAttachmentResponse(att))
+	}
+	return resp
+}
@@ -0,0 +1,89 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/models"
+)
+
+// CreateAuthorRequest is the body accepted by POST /authors.
+type CreateAuthorRequest struct {
+	Name      string    `json:"name" binding:"required"`
+	Biography string    `json:"biography"`
+	BirthDate time.Time `json:"birth_date" binding:"required"`
+}
+
+// UpdateAuthorRequest is the body accepted by PUT /authors/{id}.
+// Fields left out of the body keep their current value.
+type UpdateAuthorRequest struct {
+	Name      *string    `json:"name" binding:"omitempty,min=1"`
+	Biography *string    `json:"biography"`
+	BirthDate *time.Time `json:"birth_date"`
+}
+
+// AuthorResponse is the public representation of an author.
+type AuthorResponse struct {
+	ID        uint           `json:"id"`
+	Name      string         `json:"name"`
+	Biography string         `json:"biography"`
+	BirthDate time.Time      `json:"birth_date"`
+	Books     []BookResponse `json:"books,omitempty"`
+	Stats     *AuthorStats   `json:"stats,omitempty"`
+	// Language is the language biography is in.
+	Language string `json:"language,omitempty"`
+}
+
+// AuthorStats summarises an author's works. The year and rating fields are
+// null when the author has no books or no reviews.
+type AuthorStats struct {
+	BookCount            int64    `json:"book_count"`
+	FirstPublicationYear *int     `json:"first_publication_year"`
+	LastPublicationYear  *int     `json:"last_publication_year"`
+	AverageRating        *float64 `json:"average_rating"`
+}
+
+// ToModel builds a new author row from the request.
+func (r CreateAuthorRequest) ToModel() models.Author {
+	return models.Author{
+		Name:      r.Name,
+		Biography: r.Biography,
+		BirthDate: r.BirthDate,
+	}
+}
+
+// Apply copies the fields present in the request onto author.
+func (r UpdateAuthorRequest) Apply(author *models.Author) {
+	if r.Name != nil {
+		author.Name = *r.Name
+	}
+	if r.Biography != nil {
+		author.Biography = *r.Biography
+	}
+	if r.BirthDate != nil {
+		author.BirthDate = *r.BirthDate
+	}
+}
+
+// NewAuthorResponse maps an author row, and any preloaded books, to its response view.
+func NewAuthorResponse(author models.Author) AuthorResponse {
+	resp := AuthorResponse{
+		ID:        author.ID,
+		Name:      author.Name,
+		Biography: author.Biography,
+		BirthDate: author.BirthDate,
+		Language:  author.Language,
+	}
+	if len(author.Books) > 0 {
+		resp.Books = NewBookResponses(author.Books)
+	}
+	return resp
+}
+
+// NewAuthorResponses maps a slice of author rows to response views.
+func NewAuthorResponses(authors []models.Author) []AuthorResponse {
+	resp := make([]AuthorResponse, 0, len(authors))
+	for _, author := range authors {
+		resp = append(resp, NewAuthorResponse(author))
+	}
+	return resp
+}
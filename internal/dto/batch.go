@@ -0,0 +1,34 @@
+package dto
+
+import "encoding/json"
+
+// BatchRequest is the body accepted by POST /batch.
+type BatchRequest struct {
+	Operations []BatchOperation `json:"operations" binding:"required,min=1,max=100,dive"`
+}
+
+// BatchOperation is one create, update or delete in a batch. Update and
+// delete take the target in ID; create and update take the same body as the
+// single-resource endpoints, and creating a review also needs "book_id" in
+// the body. Ref names the operation so later ones can use its result: a
+// string "$<ref>.<field>" in ID or anywhere in Body is replaced by that
+// field of the result, e.g. "$tolkien.id".
+type BatchOperation struct {
+	Ref      string          `json:"ref" binding:"omitempty,max=64"`
+	Action   string          `json:"action" binding:"required,oneof=create update delete"`
+	Resource string          `json:"resource" binding:"required,oneof=authors books reviews"`
+	ID       json.RawMessage `json:"id,omitempty" swaggertype:"string"`
+	Body     json.RawMessage `json:"body,omitempty" swaggertype:"object"`
+}
+
+// BatchResult reports the outcome of one operation, with the status and
+// data or error the single-resource endpoint would have answered. When the
+// batch fails, operations before the failing one report 424 "Rolled back"
+// and the ones after it 424 "Not executed".
+type BatchResult struct {
+	Index  int         `json:"index"`
+	Ref    string      `json:"ref,omitempty"`
+	Status int         `json:"status"`
+	Data   interface{} `json:"data,omitempty"`
+	Error  string      `json:"error,omitempty"`
+}
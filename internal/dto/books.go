@@ -0,0 +1,216 @@
+package dto
+
+import (
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/blob"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/covers"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/models"
+)
+
+// CreateBookRequest is the body accepted by POST /books. A book needs
+// author_id, contributors, or both; author_id alone credits that author,
+// and with contributors it must be the first one with the author role.
+type CreateBookRequest struct {
+	Title           string `json:"title" binding:"required"`
+	AuthorID        uint   `json:"author_id" binding:"required_without=Contributors"`
+	ISBN            string `json:"isbn" binding:"required"`
+	PublicationYear int    `json:"publication_year" binding:"required"`
+	Description     string `json:"description"`
+	GenreIDs        []uint `json:"genre_ids" binding:"omitempty,dive,min=1"`
+	SeriesID        *uint  `json:"series_id" binding:"omitempty,min=1"`
+	SeriesPosition  *int   `json:"series_position" binding:"omitempty,min=1"`
+
+	Contributors []ContributorRequest `json:"contributors" binding:"omitempty,min=1,dive"`
+}
+
+// ContributorRequest credits an author with a role; a book's contributors
+// are ordered as they are listed.
+type ContributorRequest struct {
+	AuthorID uint   `json:"author_id" binding:"required"`
+	Role     string `json:"role" binding:"required,oneof=author editor translator illustrator"`
+}
+
+// UpdateBookRequest is the body accepted by PUT /books/{id}.
+// Fields left out of the body keep their current value.
+type UpdateBookRequest struct {
+	Title           *string `json:"title" binding:"omitempty,min=1"`
+	AuthorID        *uint   `json:"author_id" binding:"omitempty,min=1"`
+	ISBN            *string `json:"isbn" binding:"omitempty,min=1"`
+	PublicationYear *int    `json:"publication_year" binding:"omitempty,min=1"`
+	Description     *string `json:"description"`
+	// GenreIDs replaces the book's genres when present; [] removes them all.
+	GenreIDs []uint `json:"genre_ids" binding:"omitempty,dive,min=1"`
+	// Contributors replaces the book's contributors when present. Changing
+	// only author_id replaces the primary author and keeps the others.
+	Contributors []ContributorRequest `json:"contributors" binding:"omitempty,min=1,dive"`
+	// SeriesID moves the book to another series, at SeriesPosition, which
+	// is then required; 0 takes it out of its series.
+	SeriesID       *uint `json:"series_id"`
+	SeriesPosition *int  `json:"series_position" binding:"omitempty,min=1"`
+}
+
+// BookResponse is the public representation of a book.
+type BookResponse struct {
+	ID              uint                  `json:"id"`
+	Title           string                `json:"title"`
+	AuthorID        uint                  `json:"author_id"`
+	ISBN            string                `json:"isbn"`
+	PublicationYear int                   `json:"publication_year"`
+	Description     string                `json:"description"`
+	CoverURL        string                `json:"cover_url,omitempty"`
+	CoverThumbnails map[string]string     `json:"cover_thumbnails,omitempty"`
+	Author          *AuthorResponse       `json:"author,omitempty"`
+	Reviews         []ReviewResponse      `json:"reviews,omitempty"`
+	Genres          []GenreResponse       `json:"genres,omitempty"`
+	Contributors    []ContributorResponse `json:"contributors,omitempty"`
+	SeriesID        *uint                 `json:"series_id,omitempty"`
+	SeriesPosition  *int                  `json:"series_position,omitempty"`
+	Series          *SeriesResponse       `json:"series,omitempty"`
+	Editions        []EditionResponse     `json:"editions,omitempty"`
+	// Language is the language title and description are in.
+	Language string `json:"language,omitempty"`
+}
+
+// ContributorResponse is the public representation of a book contributor.
+type ContributorResponse struct {
+	AuthorID uint            `json:"author_id"`
+	Role     string          `json:"role"`
+	Position int             `json:"position"`
+	Author   *AuthorResponse `json:"author,omitempty"`
+}
+
+// ToModel builds a new book row from the request.
+func (r CreateBookRequest) ToModel() models.Book {
+	return models.Book{
+		Title:           r.Title,
+		AuthorID:        r.AuthorID,
+		ISBN:            r.ISBN,
+		PublicationYear: r.PublicationYear,
+		Description:     r.Description,
+		Contributors:    r.contributors(),
+		SeriesID:        r.SeriesID,
+		SeriesPosition:  r.SeriesPosition,
+	}
+}
+
+// contributors returns the contributors listed in the request, or the
+// author given by author_id alone.
+func (r CreateBookRequest) contributors() []models.BookContributor {
+	if len(r.Contributors) == 0 {
+		return []models.BookContributor{{AuthorID: r.AuthorID, Role: models.RoleAuthor}}
+	}
+	return NewContributors(r.Contributors)
+}
+
+// NewContributors builds contributor rows, positioned in the order listed.
+func NewContributors(reqs []ContributorRequest) []models.BookContributor {
+	contributors := make([]models.BookContributor, 0, len(reqs))
+	for i, r := range reqs {
+		contributors = append(contributors, models.BookContributor{AuthorID: r.AuthorID, Role: r.Role, Position: i})
+	}
+	return contributors
+}
+
+// Apply copies the fields present in the request onto book.
+func (r UpdateBookRequest) Apply(book *models.Book) {
+	if r.Title != nil {
+		book.Title = *r.Title
+	}
+	if r.AuthorID != nil {
+		book.AuthorID = *r.AuthorID
+	}
+	if r.ISBN != nil {
+		book.ISBN = *r.ISBN
+	}
+	if r.PublicationYear != nil {
+		book.PublicationYear = *r.PublicationYear
+	}
+	if r.Description != nil {
+		book.Description = *r.Description
+	}
+	if r.SeriesID != nil {
+		if *r.SeriesID == 0 {
+			book.SeriesID, book.SeriesPosition = nil, nil
+		} else {
+			seriesID := *r.SeriesID
+			book.SeriesID = &seriesID
+		}
+		book.Series = nil
+	}
+	if r.SeriesPosition != nil && book.SeriesID != nil {
+		position := *r.SeriesPosition
+		book.SeriesPosition = &position
+	}
+}
+
+// NewBookResponse maps a book row, and any preloaded relations, to its response view.
+func NewBookResponse(book models.Book) BookResponse {
+	resp := BookResponse{
+		ID:              book.ID,
+		Title:           book.Title,
+		AuthorID:        book.AuthorID,
+		ISBN:            book.ISBN,
+		PublicationYear: book.PublicationYear,
+		Description:     book.Description,
+		Language:        book.Language,
+	}
+	if book.CoverKey != "" {
+		resp.CoverURL = blob.URL(book.CoverKey)
+		resp.CoverThumbnails = make(map[string]string, len(covers.Sizes))
+		for _, size := range covers.Sizes {
+			resp.CoverThumbnails[size.Name] = blob.URL(covers.ThumbnailKey(book.CoverKey, size.Name))
+		}
+	}
+	if book.Author.ID != 0 {
+		author := NewAuthorResponse(book.Author)
+		resp.Author = &author
+	}
+	if len(book.Reviews) > 0 {
+		resp.Reviews = NewReviewResponses(book.Reviews)
+	}
+	if len(book.Genres) > 0 {
+		resp.Genres = NewGenreResponses(book.Genres)
+	}
+	if len(book.Contributors) > 0 {
+		resp.Contributors = NewContributorResponses(book.Contributors)
+	}
+	if book.SeriesID != nil {
+		resp.SeriesID = book.SeriesID
+		resp.SeriesPosition = book.SeriesPosition
+	}
+	if book.Series != nil {
+		series := NewSeriesResponse(*book.Series)
+		resp.Series = &series
+	}
+	if len(book.Editions) > 0 {
+		resp.Editions = NewEditionResponses(book.Editions)
+	}
+	return resp
+}
+
+// NewBookResponses maps a slice of book rows to response views.
+func NewBookResponses(books []models.Book) []BookResponse {
+	resp := make([]BookResponse, 0, len(books))
+	for _, book := range books {
+		resp = append(resp, NewBookResponse(book))
+	}
+	return resp
+}
+
+// NewContributorResponse maps a contributor row, and its preloaded author, to its response view.
+func NewContributorResponse(c models.BookContributor) ContributorResponse {
+	resp := ContributorResponse{AuthorID: c.AuthorID, Role: c.Role, Position: c.Position}
+	if c.Author.ID != 0 {
+		author := NewAuthorResponse(c.Author)
+		resp.Author = &author
+	}
+	return resp
+}
+
+// NewContributorResponses maps a slice of contributor rows to response views.
+func NewContributorResponses(contributors []models.BookContributor) []ContributorResponse {
+	resp := make([]ContributorResponse, 0, len(contributors))
+	for _, c := range contributors {
+		resp = append(resp, NewContributorResponse(c))
+	}
+	return resp
+}
@@ -0,0 +1,204 @@
+package dto
+
+import "github.com/MentalArts/go-rest-api-mehmet-pala/internal/models"
+
+// CreatePublisherRequest is the body accepted by POST /publishers.
+type CreatePublisherRequest struct {
+	Name    string `json:"name" binding:"required,max=255"`
+	Website string `json:"website" binding:"omitempty,url,max=255"`
+}
+
+// UpdatePublisherRequest is the body accepted by PUT /publishers/{id}.
+// Fields left out of the body keep their current value.
+type UpdatePublisherRequest struct {
+	Name    *string `json:"name" binding:"omitempty,min=1,max=255"`
+	Website *string `json:"website" binding:"omitempty,url,max=255"`
+}
+
+// PublisherResponse is the public representation of a publisher.
+type PublisherResponse struct {
+	ID      uint   `json:"id"`
+	Name    string `json:"name"`
+	Website string `json:"website"`
+}
+
+// CreateEditionRequest is the body accepted by POST /books/{id}/editions.
+// Language is a BCP 47 tag such as "en" or "pt-BR".
+type CreateEditionRequest struct {
+	ISBN        string `json:"isbn" binding:"required,max=20"`
+	Format      string `json:"format" binding:"required,oneof=hardcover paperback ebook audiobook"`
+	PageCount   int    `json:"page_count" binding:"omitempty,min=1"`
+	Language    string `json:"language" binding:"required,bcp47_language_tag"`
+	PublisherID *uint  `json:"publisher_id" binding:"omitempty,min=1"`
+}
+
+// UpdateEditionRequest is the body accepted by PUT /editions/{id}.
+// Fields left out of the body keep their current value; a publisher_id of
+// 0 removes the publisher.
+type UpdateEditionRequest struct {
+	ISBN        *string `json:"isbn" binding:"omitempty,min=1,max=20"`
+	Format      *string `json:"format" binding:"omitempty,oneof=hardcover paperback ebook audiobook"`
+	PageCount   *int    `json:"page_count" binding:"omitempty,min=1"`
+	Language    *string `json:"language" binding:"omitempty,bcp47_language_tag"`
+	PublisherID *uint   `json:"publisher_id"`
+}
+
+// EditionResponse is the public representation of an edition.
+type EditionResponse struct {
+	ID          uint               `json:"id"`
+	BookID      uint               `json:"book_id"`
+	ISBN        string             `json:"isbn"`
+	Format      string             `json:"format"`
+	PageCount   int                `json:"page_count"`
+	Language    string             `json:"language"`
+	PublisherID *uint              `json:"publisher_id"`
+	Publisher   *PublisherResponse `json:"publisher,omitempty"`
+}
+
+// CreateSeriesRequest is the body accepted by POST /series.
+type CreateSeriesRequest struct {
+	Name        string `json:"name" binding:"required,max=255"`
+	Description string `json:"description"`
+}
+
+// UpdateSeriesRequest is the body accepted by PUT /series/{id}.
+// Fields left out of the body keep their current value.
+type UpdateSeriesRequest struct {
+	Name        *string `json:"name" binding:"omitempty,min=1,max=255"`
+	Description *string `json:"description"`
+}
+
+// SeriesResponse is the public representation of a series. Books are
+// listed in series order.
+type SeriesResponse struct {
+	ID          uint           `json:"id"`
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Books       []BookResponse `json:"books,omitempty"`
+}
+
+// ToModel builds a new publisher row from the request.
+func (r CreatePublisherRequest) ToModel() models.Publisher {
+	return models.Publisher{Name: r.Name, Website: r.Website}
+}
+
+// Apply copies the fields present in the request onto publisher.
+func (r UpdatePublisherRequest) Apply(publisher *models.Publisher) {
+	if r.Name != nil {
+		publisher.Name = *r.Name
+	}
+	if r.Website != nil {
+		publisher.Website = *r.Website
+	}
+}
+
+// NewPublisherResponse maps a publisher row to its response view.
+func NewPublisherResponse(p models.Publisher) PublisherResponse {
+	return PublisherResponse{ID: p.ID, Name: p.Name, Website: p.Website}
+}
+
+// NewPublisherResponses maps a slice of publisher rows to response views.
+func NewPublisherResponses(publishers []models.Publisher) []PublisherResponse {
+	resp := make([]PublisherResponse, 0, len(publishers))
+	for _, p := range publishers {
+		resp = append(resp, NewPublisherResponse(p))
+	}
+	return resp
+}
+
+// ToModel builds a new edition row of the given book from the request.
+func (r CreateEditionRequest) ToModel(bookID uint) models.Edition {
+	return models.Edition{
+		BookID:      bookID,
+		ISBN:        r.ISBN,
+		Format:      r.Format,
+		PageCount:   r.PageCount,
+		Language:    r.Language,
+		PublisherID: r.PublisherID,
+	}
+}
+
+// Apply copies the fields present in the request onto edition.
+func (r UpdateEditionRequest) Apply(edition *models.Edition) {
+	if r.ISBN != nil {
+		edition.ISBN = *r.ISBN
+	}
+	if r.Format != nil {
+		edition.Format = *r.Format
+	}
+	if r.PageCount != nil {
+		edition.PageCount = *r.PageCount
+	}
+	if r.Language != nil {
+		edition.Language = *r.Language
+	}
+	if r.PublisherID != nil {
+		if *r.PublisherID == 0 {
+			edition.PublisherID = nil
+		} else {
+			publisherID := *r.PublisherID
+			edition.PublisherID = &publisherID
+		}
+		edition.Publisher = models.Publisher{}
+	}
+}
+
+// NewEditionResponse maps an edition row, and its preloaded publisher, to its response view.
+func NewEditionResponse(e models.Edition) EditionResponse {
+	resp := EditionResponse{
+		ID:          e.ID,
+		BookID:      e.BookID,
+		ISBN:        e.ISBN,
+		Format:      e.Format,
+		PageCount:   e.PageCount,
+		Language:    e.Language,
+		PublisherID: e.PublisherID,
+	}
+	if e.Publisher.ID != 0 {
+		publisher := NewPublisherResponse(e.Publisher)
+		resp.Publisher = &publisher
+	}
+	return resp
+}
+
+// NewEditionResponses maps a slice of edition rows to response views.
+func NewEditionResponses(editions []models.Edition) []EditionResponse {
+	resp := make([]EditionResponse, 0, len(editions))
+	for _, e := range editions {
+		resp = append(resp, NewEditionResponse(e))
+	}
+	return resp
+}
+
+// ToModel builds a new series row from the request.
+func (r CreateSeriesRequest) ToModel() models.Series {
+	return models.Series{Name: r.Name, Description: r.Description}
+}
+
+// Apply copies the fields present in the request onto series.
+func (r UpdateSeriesRequest) Apply(series *models.Series) {
+	if r.Name != nil {
+		series.Name = *r.Name
+	}
+	if r.Description != nil {
+		series.Description = *r.Description
+	}
+}
+
+// NewSeriesResponse maps a series row, and any preloaded books, to its response view.
+func NewSeriesResponse(s models.Series) SeriesResponse {
+	resp := SeriesResponse{ID: s.ID, Name: s.Name, Description: s.Description}
+	if len(s.Books) > 0 {
+		resp.Books = NewBookResponses(s.Books)
+	}
+	return resp
+}
+
+// NewSeriesResponses maps a slice of series rows to response views.
+func NewSeriesResponses(series []models.Series) []SeriesResponse {
+	resp := make([]SeriesResponse, 0, len(series))
+	for _, s := range series {
+		resp = append(resp, NewSeriesResponse(s))
+	}
+	return resp
+}
@@ -0,0 +1,87 @@
+package dto
+
+import "github.com/MentalArts/go-rest-api-mehmet-pala/internal/models"
+
+// CreateGenreRequest is the body accepted by POST /genres. The slug, used
+// to filter books by genre, is derived from the name when left out.
+type CreateGenreRequest struct {
+	Name        string `json:"name" binding:"required,max=100"`
+	Slug        string `json:"slug" binding:"omitempty,max=100"`
+	Description string `json:"description"`
+	ParentID    *uint  `json:"parent_id" binding:"omitempty,min=1"`
+}
+
+// UpdateGenreRequest is the body accepted by PUT /genres/{id}.
+// Fields left out of the body keep their current value; a parent_id of 0
+// makes the genre a top-level one.
+type UpdateGenreRequest struct {
+	Name        *string `json:"name" binding:"omitempty,min=1,max=100"`
+	Slug        *string `json:"slug" binding:"omitempty,min=1,max=100"`
+	Description *string `json:"description"`
+	ParentID    *uint   `json:"parent_id"`
+}
+
+// GenreResponse is the public representation of a genre.
+type GenreResponse struct {
+	ID          uint            `json:"id"`
+	Name        string          `json:"name"`
+	Slug        string          `json:"slug"`
+	Description string          `json:"description"`
+	ParentID    *uint           `json:"parent_id"`
+	Children    []GenreResponse `json:"children,omitempty"`
+}
+
+// ToModel builds a new genre row from the request.
+func (r CreateGenreRequest) ToModel() models.Genre {
+	return models.Genre{
+		Name:        r.Name,
+		Slug:        r.Slug,
+		Description: r.Description,
+		ParentID:    r.ParentID,
+	}
+}
+
+// Apply copies the fields present in the request onto genre.
+func (r UpdateGenreRequest) Apply(genre *models.Genre) {
+	if r.Name != nil {
+		genre.Name = *r.Name
+	}
+	if r.Slug != nil {
+		genre.Slug = *r.Slug
+	}
+	if r.Description != nil {
+		genre.Description = *r.Description
+	}
+	if r.ParentID != nil {
+		if *r.ParentID == 0 {
+			genre.ParentID = nil
+		} else {
+			parentID := *r.ParentID
+			genre.ParentID = &parentID
+		}
+	}
+}
+
+// NewGenreResponse maps a genre row, and any preloaded children, to its response view.
+func NewGenreResponse(genre models.Genre) GenreResponse {
+	resp := GenreResponse{
+		ID:          genre.ID,
+		Name:        genre.Name,
+		Slug:        genre.Slug,
+		Description: genre.Description,
+		ParentID:    genre.ParentID,
+	}
+	if len(genre.Children) > 0 {
+		resp.Children = NewGenreResponses(genre.Children)
+	}
+	return resp
+}
+
+// NewGenreResponses maps a slice of genre rows to response views.
+func NewGenreResponses(genres []models.Genre) []GenreResponse {
+	resp := make([]GenreResponse, 0, len(genres))
+	for _, genre := range genres {
+		resp = append(resp, NewGenreResponse(genre))
+	}
+	return resp
+}
@@ -0,0 +1,114 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/models"
+)
+
+// CreateReviewRequest is the body accepted by POST /books/{id}/reviews.
+// The book is taken from the path, not the body.
+type CreateReviewRequest struct {
+	Rating     int       `json:"rating" binding:"required,min=1,max=5"`
+	Comment    string    `json:"comment" binding:"required"`
+	DatePosted time.Time `json:"date_posted"`
+}
+
+// UpdateReviewRequest is the body accepted by PUT /reviews/{id}.
+// Fields left out of the body keep their current value.
+type UpdateReviewRequest struct {
+	Rating     *int       `json:"rating" binding:"omitempty,min=1,max=5"`
+	Comment    *string    `json:"comment" binding:"omitempty,min=1"`
+	DatePosted *time.Time `json:"date_posted"`
+}
+
+// ModerateReviewRequest is the body accepted by PUT /moderation/reviews/{id}.
+type ModerateReviewRequest struct {
+	Status string `json:"status" binding:"required,oneof=approved rejected"`
+	Reason string `json:"reason"`
+}
+
+// ReviewResponse is the public representation of a review. Status is
+// pending, approved or rejected.
+type ReviewResponse struct {
+	ID         uint      `json:"id"`
+	BookID     uint      `json:"book_id"`
+	Rating     int       `json:"rating"`
+	Comment    string    `json:"comment"`
+	DatePosted time.Time `json:"date_posted"`
+	Status     string    `json:"status"`
+}
+
+// ModeratedReviewResponse is a review as moderators see it, with the
+// reason it was held or rejected, or the note of the moderator who last
+// decided on it.
+type ModeratedReviewResponse struct {
+	ReviewResponse
+	ModerationReason string `json:"moderation_reason,omitempty"`
+}
+
+// ToModel builds a new review row for bookID. DatePosted defaults to now.
+func (r CreateReviewRequest) ToModel(bookID uint) models.Review {
+	review := models.Review{
+		BookID:     bookID,
+		Rating:     r.Rating,
+		Comment:    r.Comment,
+		DatePosted: r.DatePosted,
+	}
+	if review.DatePosted.IsZero() {
+		review.DatePosted = time.Now()
+	}
+	return review
+}
+
+// Apply copies the fields present in the request onto review.
+func (r UpdateReviewRequest) Apply(review *models.Review) {
+	if r.Rating != nil {
+		review.Rating = *r.Rating
+	}
+	if r.Comment != nil {
+		review.Comment = *r.Comment
+	}
+	if r.DatePosted != nil {
+		review.DatePosted = *r.DatePosted
+	}
+}
+
+// NewReviewResponse maps a review row to its response view.
+func NewReviewResponse(review models.Review) ReviewResponse {
+	return ReviewResponse{
+		ID:         review.ID,
+		BookID:     review.BookID,
+		Rating:     review.Rating,
+		Comment:    review.Comment,
+		DatePosted: review.DatePosted,
+		Status:     review.Status,
+	}
+}
+
+// NewReviewResponses maps a slice of review rows to response views.
+func NewReviewResponses(reviews []models.Review) []ReviewResponse {
+	resp := make([]ReviewResponse, 0, len(reviews))
+	for _, review := range reviews {
+		resp = append(resp, NewReviewResponse(review))
+	}
+	return resp
+}
+
+// NewModeratedReviewResponse maps a review row to the moderators' view.
+func NewModeratedReviewResponse(review models.Review) ModeratedReviewResponse {
+	return ModeratedReviewResponse{
+		ReviewResponse:   NewReviewResponse(review),
+		ModerationReason: review.ModerationReason,
+	}
+}
+
+// NewModeratedReviewResponses maps a slice of review rows to the
+// moderators' view.
+func NewModeratedReviewResponses(reviews []models.Review) []ModeratedReviewResponse {
+	resp := make([]ModeratedReviewResponse, 0, len(reviews))
+	for _, review := range reviews {
+		resp = append(resp, NewModeratedReviewResponse(review))
+	}
+	return resp
+}
@@ -0,0 +1,68 @@
+package dto
+
+import "github.com/MentalArts/go-rest-api-mehmet-pala/internal/models"
+
+// BookTranslationRequest is the body accepted by
+// PUT /books/{id}/translations/{language}. A field left empty is served in
+// the default language.
+type BookTranslationRequest struct {
+	Title       string `json:"title" binding:"required_without=Description"`
+	Description string `json:"description"`
+}
+
+// AuthorTranslationRequest is the body accepted by
+// PUT /authors/{id}/translations/{language}.
+type AuthorTranslationRequest struct {
+	Biography string `json:"biography" binding:"required"`
+}
+
+// BookTranslationResponse is the public representation of a book translation.
+type BookTranslationResponse struct {
+	Language    string `json:"language"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+// AuthorTranslationResponse is the public representation of an author translation.
+type AuthorTranslationResponse struct {
+	Language  string `json:"language"`
+	Biography string `json:"biography"`
+}
+
+// ToModel builds the translation row of a book from the request.
+func (r BookTranslationRequest) ToModel(bookID uint, lang string) models.BookTranslation {
+	return models.BookTranslation{BookID: bookID, Language: lang, Title: r.Title, Description: r.Description}
+}
+
+// ToModel builds the translation row of an author from the request.
+func (r AuthorTranslationRequest) ToModel(authorID uint, lang string) models.AuthorTranslation {
+	return models.AuthorTranslation{AuthorID: authorID, Language: lang, Biography: r.Biography}
+}
+
+// NewBookTranslationResponses maps book translation rows to response views.
+func NewBookTranslationResponses(translations []models.BookTranslation) []BookTranslationResponse {
+	resp := make([]BookTranslationResponse, 0, len(translations))
+	for _, t := range translations {
+		resp = append(resp, NewBookTranslationResponse(t))
+	}
+	return resp
+}
+
+// NewBookTranslationResponse maps a book translation row to its response view.
+func NewBookTranslationResponse(t models.BookTranslation) BookTranslationResponse {
+	return BookTranslationResponse{Language: t.Language, Title: t.Title, Description: t.Description}
+}
+
+// NewAuthorTranslationResponses maps author translation rows to response views.
+func NewAuthorTranslationResponses(translations []models.AuthorTranslation) []AuthorTranslationResponse {
+	resp := make([]AuthorTranslationResponse, 0, len(translations))
+	for _, t := range translations {
+		resp = append(resp, NewAuthorTranslationResponse(t))
+	}
+	return resp
+}
+
+// NewAuthorTranslationResponse maps an author translation row to its response view.
+func NewAuthorTranslationResponse(t models.AuthorTranslation) AuthorTranslationResponse {
+	return AuthorTranslationResponse{Language: t.Language, Biography: t.Biography}
+}
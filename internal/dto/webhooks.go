@@ -0,0 +1,115 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/models"
+)
+
+// CreateWebhookRequest is the body accepted by POST /webhooks. A secret is
+// generated when none is given. The oneof list of event types must match
+// webhooks.Events.
+type CreateWebhookRequest struct {
+	URL    string   `json:"url" binding:"required,url,startswith=http"`
+	Secret string   `json:"secret" binding:"omitempty,min=16"`
+	Events []string `json:"events" binding:"required,min=1,dive,oneof=author.created author.updated author.deleted book.created book.updated book.deleted review.created review.updated review.deleted"`
+}
+
+// UpdateWebhookRequest is the body accepted by PUT /webhooks/{id}.
+// Fields left out of the body keep their current value.
+type UpdateWebhookRequest struct {
+	URL    *string  `json:"url" binding:"omitempty,url,startswith=http"`
+	Secret *string  `json:"secret" binding:"omitempty,min=16"`
+	Events []string `json:"events" binding:"omitempty,min=1,dive,oneof=author.created author.updated author.deleted book.created book.updated book.deleted review.created review.updated review.deleted"`
+	Active *bool    `json:"active"`
+}
+
+// WebhookResponse is the public representation of a subscription. The
+// secret is only included in the response to its creation.
+type WebhookResponse struct {
+	ID        uint      `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	Active    bool      `json:"active"`
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// WebhookDeliveryResponse is the public representation of a delivery.
+type WebhookDeliveryResponse struct {
+	ID             uint            `json:"id"`
+	SubscriptionID uint            `json:"subscription_id"`
+	EventID        string          `json:"event_id"`
+	Event          string          `json:"event"`
+	Payload        json.RawMessage `json:"payload" swaggertype:"object"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	ResponseStatus int             `json:"response_status,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+// ToModel builds a new subscription row.
+func (r CreateWebhookRequest) ToModel() models.WebhookSubscription {
+	return models.WebhookSubscription{URL: r.URL, Secret: r.Secret, Events: r.Events}
+}
+
+// Apply copies the fields present in the request onto sub.
+func (r UpdateWebhookRequest) Apply(sub *models.WebhookSubscription) {
+	if r.URL != nil {
+		sub.URL = *r.URL
+	}
+	if r.Secret != nil {
+		sub.Secret = *r.Secret
+	}
+	if r.Events != nil {
+		sub.Events = r.Events
+	}
+	if r.Active != nil {
+		sub.Active = *r.Active
+	}
+}
+
+// NewWebhookResponse maps a subscription to its response, without the secret.
+func NewWebhookResponse(s models.WebhookSubscription) WebhookResponse {
+	return WebhookResponse{ID: s.ID, URL: s.URL, Events: s.Events, Active: s.Active, CreatedAt: s.CreatedAt}
+}
+
+// NewWebhookResponses maps a slice of subscriptions to their responses.
+func NewWebhookResponses(subs []models.WebhookSubscription) []WebhookResponse {
+	resp := make([]WebhookResponse, 0, len(subs))
+	for _, sub := range subs {
+		resp = append(resp, NewWebhookResponse(sub))
+	}
+	return resp
+}
+
+// NewWebhookDeliveryResponse maps a delivery to its response.
+func NewWebhookDeliveryResponse(d models.WebhookDelivery) WebhookDeliveryResponse {
+	return WebhookDeliveryResponse{
+		ID:             d.ID,
+		SubscriptionID: d.SubscriptionID,
+		EventID:        d.EventID,
+		Event:          d.Event,
+		Payload:        json.RawMessage(d.Payload),
+		Status:         d.Status,
+		Attempts:       d.Attempts,
+		ResponseStatus: d.ResponseStatus,
+		LastError:      d.LastError,
+		NextAttemptAt:  d.NextAttemptAt,
+		DeliveredAt:    d.DeliveredAt,
+		CreatedAt:      d.CreatedAt,
+	}
+}
+
+// NewWebhookDeliveryResponses maps a slice of deliveries to their responses.
+func NewWebhookDeliveryResponses(deliveries []models.WebhookDelivery) []WebhookDeliveryResponse {
+	resp := make([]WebhookDeliveryResponse, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		resp = append(resp, NewWebhookDeliveryResponse(delivery))
+	}
+	return resp
+}
@@ -0,0 +1,182 @@
+// Package events fans the outbox's live pub/sub channel out to Server-Sent
+// Events clients. Each instance holds one Redis subscription, so clients
+// see changes made through any replica, and clients resuming after a
+// disconnect are caught up from the outbox table.
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync"
+
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/cache"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/db"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/models"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/outbox"
+	"github.com/go-redis/redis/v8"
+	"gorm.io/gorm"
+)
+
+// bufferSize is how many events a subscriber may fall behind before it is
+// disconnected; its client then resumes with Last-Event-ID.
+const bufferSize = 64
+
+// Filter selects events. Zero fields match everything.
+type Filter struct {
+	// Types restricts events to these types, e.g. "review.created".
+	Types []string
+	// BookID restricts events to reviews of this book.
+	BookID uint
+}
+
+// Match reports whether msg passes the filter.
+func (f Filter) Match(msg outbox.Message) bool {
+	if len(f.Types) > 0 && !contains(f.Types, msg.Type) {
+		return false
+	}
+	if f.BookID != 0 {
+		if msg.AggregateType != outbox.Review {
+			return false
+		}
+		var review struct {
+			BookID uint `json:"book_id"`
+		}
+		if json.Unmarshal(msg.Data, &review) != nil || review.BookID != f.BookID {
+			return false
+		}
+	}
+	return true
+}
+
+// apply adds the filter's conditions to an outbox query.
+func (f Filter) apply(tx *gorm.DB) *gorm.DB {
+	if len(f.Types) > 0 {
+		tx = tx.Where("event_type IN ?", f.Types)
+	}
+	if f.BookID != 0 {
+		tx = tx.Where("aggregate_type = ? AND (payload::jsonb->>'book_id')::bigint = ?", outbox.Review, f.BookID)
+	}
+	return tx
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Subscription receives live events matching its filter on C. C is closed
+// when the subscriber falls too far behind or the hub stops.
+type Subscription struct {
+	C      <-chan outbox.Message
+	c      chan outbox.Message
+	filter Filter
+}
+
+var (
+	mu      sync.Mutex
+	subs    = make(map[*Subscription]struct{})
+	stopped bool
+	pubsub  *redis.PubSub
+	running sync.WaitGroup
+)
+
+// Start subscribes to channel and forwards its events to subscribers until
+// Stop is called.
+func Start(channel string) {
+	pubsub = cache.RDB.Subscribe(context.Background(), channel)
+	running.Add(1)
+	go func() {
+		defer running.Done()
+		// Channel reconnects after network errors and is closed by Close
+		for m := range pubsub.Channel() {
+			var msg outbox.Message
+			if err := json.Unmarshal([]byte(m.Payload), &msg); err != nil {
+				slog.Warn("Ignoring malformed event", "channel", m.Channel, "error", err)
+				continue
+			}
+			broadcast(msg)
+		}
+	}()
+}
+
+// Stop closes the Redis subscription and every subscriber, ending their
+// streams so the HTTP server can shut down.
+func Stop() {
+	mu.Lock()
+	stopped = true
+	for sub := range subs {
+		close(sub.c)
+		delete(subs, sub)
+	}
+	mu.Unlock()
+
+	if pubsub != nil {
+		if err := pubsub.Close(); err != nil {
+			slog.Error("Closing event subscription failed", "error", err)
+		}
+	}
+	running.Wait()
+}
+
+// Subscribe registers a subscriber for live events matching filter.
+func Subscribe(filter Filter) *Subscription {
+	c := make(chan outbox.Message, bufferSize)
+	sub := &Subscription{C: c, c: c, filter: filter}
+	mu.Lock()
+	defer mu.Unlock()
+	if stopped {
+		close(c)
+		return sub
+	}
+	subs[sub] = struct{}{}
+	return sub
+}
+
+// Unsubscribe removes the subscriber. It is safe to call more than once.
+func Unsubscribe(sub *Subscription) {
+	mu.Lock()
+	defer mu.Unlock()
+	if _, ok := subs[sub]; ok {
+		close(sub.c)
+		delete(subs, sub)
+	}
+}
+
+func broadcast(msg outbox.Message) {
+	mu.Lock()
+	defer mu.Unlock()
+	for sub := range subs {
+		if !sub.filter.Match(msg) {
+			continue
+		}
+		select {
+		case sub.c <- msg:
+		default:
+			slog.Warn("Disconnecting slow event subscriber", "event_id", msg.ID)
+			close(sub.c)
+			delete(subs, sub)
+		}
+	}
+}
+
+// Since returns up to limit published events after afterID matching filter,
+// oldest first, for a client resuming its stream.
+func Since(ctx context.Context, afterID uint, filter Filter, limit int) ([]outbox.Message, error) {
+	var events []models.OutboxEvent
+	err := filter.apply(db.Reader(ctx)).
+		Where("id > ? AND published_at IS NOT NULL", afterID).
+		Order("id").Limit(limit).Find(&events).Error
+	if err != nil {
+		return nil, err
+	}
+	msgs := make([]outbox.Message, 0, len(events))
+	for _, event := range events {
+		msgs = append(msgs, outbox.NewMessage(event))
+	}
+	return msgs, nil
+}
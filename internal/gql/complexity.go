@@ -0,0 +1,84 @@
+package gql
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+const (
+	// maxComplexity bounds the estimated number of fields a query resolves.
+	maxComplexity = 1000
+	// maxDepth bounds selection nesting, e.g. book > author > books > reviews.
+	maxDepth = 6
+)
+
+var complexitySchema = gqlparser.MustLoadSchema(&ast.Source{Name: "schema.graphql", Input: schemaSDL})
+
+// complexity estimates the cost of an operation: each field costs one, and
+// list fields multiply the cost of their selection by the requested page
+// size (first or limit), or by the default size when none is given.
+func complexity(query, operationName string, variables map[string]interface{}) (int, error) {
+	doc, errs := gqlparser.LoadQuery(complexitySchema, query)
+	if len(errs) > 0 {
+		return 0, errs
+	}
+	op := doc.Operations.ForName(operationName)
+	if op == nil {
+		return 0, fmt.Errorf("operation %q not found", operationName)
+	}
+	return selectionCost(op.SelectionSet, variables), nil
+}
+
+func selectionCost(set ast.SelectionSet, variables map[string]interface{}) int {
+	cost := 0
+	for _, sel := range set {
+		switch s := sel.(type) {
+		case *ast.Field:
+			// Introspection is bounded by the schema itself.
+			if strings.HasPrefix(s.Name, "__") {
+				continue
+			}
+			fieldCost := 1 + selectionCost(s.SelectionSet, variables)
+			if s.Definition != nil && s.Definition.Type.Elem != nil {
+				fieldCost *= requestedSize(s, variables)
+			}
+			cost += fieldCost
+		case *ast.InlineFragment:
+			cost += selectionCost(s.SelectionSet, variables)
+		case *ast.FragmentSpread:
+			if s.Definition != nil {
+				cost += selectionCost(s.Definition.SelectionSet, variables)
+			}
+		}
+	}
+	return cost
+}
+
+func requestedSize(field *ast.Field, variables map[string]interface{}) int {
+	args := field.ArgumentMap(variables)
+	for _, name := range []string{"first", "limit"} {
+		var n int
+		switch v := args[name].(type) {
+		case int64:
+			n = int(v)
+		case int:
+			n = v
+		case float64:
+			n = int(v)
+		case json.Number:
+			i, _ := v.Int64()
+			n = int(i)
+		default:
+			continue
+		}
+		if n < 1 || n > maxListSize {
+			n = maxListSize
+		}
+		return n
+	}
+	return 10
+}
@@ -0,0 +1,51 @@
+// Package gql serves the library schema over GraphQL at /graphql.
+package gql
+
+import (
+	_ "embed"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+//go:embed schema.graphql
+var schemaSDL string
+
+type request struct {
+	Query         string                 `json:"query" binding:"required"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Handler executes GraphQL queries and mutations sent as JSON POST bodies.
+// Operations over the complexity limit are rejected before any resolver runs.
+func Handler() gin.HandlerFunc {
+	schema := graphql.MustParseSchema(schemaSDL, &Resolver{},
+		graphql.MaxDepth(maxDepth),
+		graphql.MaxParallelism(10),
+	)
+
+	return func(c *gin.Context) {
+		var req request
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": []gin.H{{"message": err.Error()}}})
+			return
+		}
+
+		cost, err := complexity(req.Query, req.OperationName, req.Variables)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": []gin.H{{"message": err.Error()}}})
+			return
+		}
+		if cost > maxComplexity {
+			msg := fmt.Sprintf("query complexity %d exceeds the limit of %d", cost, maxComplexity)
+			c.JSON(http.StatusBadRequest, gin.H{"errors": []gin.H{{"message": msg}}})
+			return
+		}
+
+		ctx := withLoaders(c.Request.Context())
+		c.JSON(http.StatusOK, schema.Exec(ctx, req.Query, req.OperationName, req.Variables))
+	}
+}
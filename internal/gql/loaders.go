@@ -0,0 +1,117 @@
+package gql
+
+import (
+	"context"
+
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/models"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/services"
+	"github.com/graph-gophers/dataloader/v7"
+)
+
+// loaders batch the relation lookups made while resolving one request, so
+// a list of books loads its authors and reviews in one query each instead
+// of one query per book.
+type loaders struct {
+	authorByID    *dataloader.Loader[uint, models.Author]
+	bookByID      *dataloader.Loader[uint, models.Book]
+	booksByAuthor *dataloader.Loader[uint, []models.Book]
+	reviewsByBook *dataloader.Loader[uint, []models.Review]
+}
+
+type loadersKey struct{}
+
+// withLoaders attaches a fresh set of loaders to ctx. Loaders cache results,
+// so they must not outlive a single request.
+func withLoaders(ctx context.Context) context.Context {
+	l := &loaders{
+		authorByID:    dataloader.NewBatchedLoader(batchAuthors),
+		bookByID:      dataloader.NewBatchedLoader(batchBooks),
+		booksByAuthor: dataloader.NewBatchedLoader(batchBooksByAuthor),
+		reviewsByBook: dataloader.NewBatchedLoader(batchReviewsByBook),
+	}
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
+
+func batchAuthors(ctx context.Context, ids []uint) []*dataloader.Result[models.Author] {
+	authors, err := services.AuthorsByIDs(ctx, ids)
+	if err != nil {
+		return failAll[models.Author](len(ids), err)
+	}
+	byID := make(map[uint]models.Author, len(authors))
+	for _, a := range authors {
+		byID[a.ID] = a
+	}
+	results := make([]*dataloader.Result[models.Author], len(ids))
+	for i, id := range ids {
+		if a, ok := byID[id]; ok {
+			results[i] = &dataloader.Result[models.Author]{Data: a}
+		} else {
+			results[i] = &dataloader.Result[models.Author]{Error: services.ErrAuthorNotFound}
+		}
+	}
+	return results
+}
+
+func batchBooks(ctx context.Context, ids []uint) []*dataloader.Result[models.Book] {
+	books, err := services.BooksByIDs(ctx, ids)
+	if err != nil {
+		return failAll[models.Book](len(ids), err)
+	}
+	byID := make(map[uint]models.Book, len(books))
+	for _, b := range books {
+		byID[b.ID] = b
+	}
+	results := make([]*dataloader.Result[models.Book], len(ids))
+	for i, id := range ids {
+		if b, ok := byID[id]; ok {
+			results[i] = &dataloader.Result[models.Book]{Data: b}
+		} else {
+			results[i] = &dataloader.Result[models.Book]{Error: services.ErrBookNotFound}
+		}
+	}
+	return results
+}
+
+func batchBooksByAuthor(ctx context.Context, authorIDs []uint) []*dataloader.Result[[]models.Book] {
+	books, err := services.BooksByAuthorIDs(ctx, authorIDs)
+	if err != nil {
+		return failAll[[]models.Book](len(authorIDs), err)
+	}
+	grouped := make(map[uint][]models.Book, len(authorIDs))
+	for _, b := range books {
+		grouped[b.AuthorID] = append(grouped[b.AuthorID], b)
+	}
+	results := make([]*dataloader.Result[[]models.Book], len(authorIDs))
+	for i, id := range authorIDs {
+		results[i] = &dataloader.Result[[]models.Book]{Data: grouped[id]}
+	}
+	return results
+}
+
+func batchReviewsByBook(ctx context.Context, bookIDs []uint) []*dataloader.Result[[]models.Review] {
+	reviews, err := services.ReviewsByBookIDs(ctx, bookIDs)
+	if err != nil {
+		return failAll[[]models.Review](len(bookIDs), err)
+	}
+	grouped := make(map[uint][]models.Review, len(bookIDs))
+	for _, r := range reviews {
+		grouped[r.BookID] = append(grouped[r.BookID], r)
+	}
+	results := make([]*dataloader.Result[[]models.Review], len(bookIDs))
+	for i, id := range bookIDs {
+		results[i] = &dataloader.Result[[]models.Review]{Data: grouped[id]}
+	}
+	return results
+}
+
+func failAll[V any](n int, err error) []*dataloader.Result[V] {
+	results := make([]*dataloader.Result[V], n)
+	for i := range results {
+		results[i] = &dataloader.Result[V]{Error: err}
+	}
+	return results
+}
@@ -0,0 +1,320 @@
+package gql
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/dto"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/services"
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+// Resolver is the root resolver for both queries and mutations. Reads and
+// writes go through the services package, so GraphQL enforces the same
+// validation and persistence rules as the REST handlers.
+type Resolver struct{}
+
+type pageArgs struct {
+	Page  int32
+	Limit int32
+}
+
+// offsetLimit mirrors the REST pagination defaults and bounds.
+func (a pageArgs) offsetLimit() (int, int) {
+	page, limit := 1, 10
+	if a.Page > 0 {
+		page = int(a.Page)
+	}
+	if a.Limit > 0 && a.Limit <= maxListSize {
+		limit = int(a.Limit)
+	}
+	return (page - 1) * limit, limit
+}
+
+func (r *Resolver) Book(ctx context.Context, args struct{ ID graphql.ID }) (*bookResolver, error) {
+	id, err := fromID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	book, err := services.GetBook(ctx, id)
+	if errors.Is(err, services.ErrBookNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &bookResolver{book: book}, nil
+}
+
+func (r *Resolver) Books(ctx context.Context, args pageArgs) ([]*bookResolver, error) {
+	offset, limit := args.offsetLimit()
+	books, err := services.ListBooks(ctx, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*bookResolver, 0, len(books))
+	for _, book := range books {
+		out = append(out, &bookResolver{book: book})
+	}
+	return out, nil
+}
+
+func (r *Resolver) Author(ctx context.Context, args struct{ ID graphql.ID }) (*authorResolver, error) {
+	id, err := fromID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	author, err := services.GetAuthor(ctx, id)
+	if errors.Is(err, services.ErrAuthorNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &authorResolver{author: author}, nil
+}
+
+func (r *Resolver) Authors(ctx context.Context, args pageArgs) ([]*authorResolver, error) {
+	offset, limit := args.offsetLimit()
+	authors, err := services.ListAuthors(ctx, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*authorResolver, 0, len(authors))
+	for _, author := range authors {
+		out = append(out, &authorResolver{author: author})
+	}
+	return out, nil
+}
+
+func (r *Resolver) Reviews(ctx context.Context, args struct{ BookID graphql.ID }) ([]*reviewResolver, error) {
+	bookID, err := fromID(args.BookID)
+	if err != nil {
+		return nil, err
+	}
+	reviews, err := services.ListReviews(ctx, bookID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*reviewResolver, 0, len(reviews))
+	for _, review := range reviews {
+		out = append(out, &reviewResolver{review: review})
+	}
+	return out, nil
+}
+
+type createBookInput struct {
+	Title           string
+	AuthorID        graphql.ID
+	ISBN            string
+	PublicationYear int32
+	Description     *string
+}
+
+func (r *Resolver) CreateBook(ctx context.Context, args struct{ Input createBookInput }) (*bookResolver, error) {
+	authorID, err := fromID(args.Input.AuthorID)
+	if err != nil {
+		return nil, err
+	}
+	req := dto.CreateBookRequest{
+		Title:           args.Input.Title,
+		AuthorID:        authorID,
+		ISBN:            args.Input.ISBN,
+		PublicationYear: int(args.Input.PublicationYear),
+	}
+	if args.Input.Description != nil {
+		req.Description = *args.Input.Description
+	}
+	book, err := services.CreateBook(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return &bookResolver{book: book}, nil
+}
+
+type updateBookInput struct {
+	Title           *string
+	AuthorID        *graphql.ID
+	ISBN            *string
+	PublicationYear *int32
+	Description     *string
+}
+
+func (r *Resolver) UpdateBook(ctx context.Context, args struct {
+	ID    graphql.ID
+	Input updateBookInput
+}) (*bookResolver, error) {
+	id, err := fromID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	req := dto.UpdateBookRequest{
+		Title:       args.Input.Title,
+		ISBN:        args.Input.ISBN,
+		Description: args.Input.Description,
+	}
+	if args.Input.AuthorID != nil {
+		authorID, err := fromID(*args.Input.AuthorID)
+		if err != nil {
+			return nil, err
+		}
+		req.AuthorID = &authorID
+	}
+	if args.Input.PublicationYear != nil {
+		year := int(*args.Input.PublicationYear)
+		req.PublicationYear = &year
+	}
+	book, err := services.UpdateBook(ctx, id, req)
+	if err != nil {
+		return nil, err
+	}
+	return &bookResolver{book: book}, nil
+}
+
+func (r *Resolver) DeleteBook(ctx context.Context, args struct{ ID graphql.ID }) (*bookResolver, error) {
+	id, err := fromID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	book, err := services.DeleteBook(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &bookResolver{book: book}, nil
+}
+
+type createAuthorInput struct {
+	Name      string
+	Biography *string
+	BirthDate graphql.Time
+}
+
+func (r *Resolver) CreateAuthor(ctx context.Context, args struct{ Input createAuthorInput }) (*authorResolver, error) {
+	req := dto.CreateAuthorRequest{
+		Name:      args.Input.Name,
+		BirthDate: args.Input.BirthDate.Time,
+	}
+	if args.Input.Biography != nil {
+		req.Biography = *args.Input.Biography
+	}
+	author, err := services.CreateAuthor(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return &authorResolver{author: author}, nil
+}
+
+type updateAuthorInput struct {
+	Name      *string
+	Biography *string
+	BirthDate *graphql.Time
+}
+
+func (r *Resolver) UpdateAuthor(ctx context.Context, args struct {
+	ID    graphql.ID
+	Input updateAuthorInput
+}) (*authorResolver, error) {
+	id, err := fromID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	req := dto.UpdateAuthorRequest{
+		Name:      args.Input.Name,
+		Biography: args.Input.Biography,
+		BirthDate: timePtr(args.Input.BirthDate),
+	}
+	author, err := services.UpdateAuthor(ctx, id, req)
+	if err != nil {
+		return nil, err
+	}
+	return &authorResolver{author: author}, nil
+}
+
+func (r *Resolver) DeleteAuthor(ctx context.Context, args struct{ ID graphql.ID }) (*authorResolver, error) {
+	id, err := fromID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	author, err := services.DeleteAuthor(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &authorResolver{author: author}, nil
+}
+
+type createReviewInput struct {
+	Rating     int32
+	Comment    string
+	DatePosted *graphql.Time
+}
+
+func (r *Resolver) CreateReview(ctx context.Context, args struct {
+	BookID graphql.ID
+	Input  createReviewInput
+}) (*reviewResolver, error) {
+	bookID, err := fromID(args.BookID)
+	if err != nil {
+		return nil, err
+	}
+	req := dto.CreateReviewRequest{
+		Rating:  int(args.Input.Rating),
+		Comment: args.Input.Comment,
+	}
+	if args.Input.DatePosted != nil {
+		req.DatePosted = args.Input.DatePosted.Time
+	}
+	review, err := services.CreateReview(ctx, bookID, req)
+	if err != nil {
+		return nil, err
+	}
+	return &reviewResolver{review: review}, nil
+}
+
+type updateReviewInput struct {
+	Rating     *int32
+	Comment    *string
+	DatePosted *graphql.Time
+}
+
+func (r *Resolver) UpdateReview(ctx context.Context, args struct {
+	ID    graphql.ID
+	Input updateReviewInput
+}) (*reviewResolver, error) {
+	id, err := fromID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	req := dto.UpdateReviewRequest{
+		Comment:    args.Input.Comment,
+		DatePosted: timePtr(args.Input.DatePosted),
+	}
+	if args.Input.Rating != nil {
+		rating := int(*args.Input.Rating)
+		req.Rating = &rating
+	}
+	review, err := services.UpdateReview(ctx, id, req)
+	if err != nil {
+		return nil, err
+	}
+	return &reviewResolver{review: review}, nil
+}
+
+func (r *Resolver) DeleteReview(ctx context.Context, args struct{ ID graphql.ID }) (*reviewResolver, error) {
+	id, err := fromID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	review, err := services.DeleteReview(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &reviewResolver{review: review}, nil
+}
+
+func timePtr(t *graphql.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	return &t.Time
+}
@@ -0,0 +1,121 @@
+package gql
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/blob"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/models"
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+// maxListSize caps the page and relation sizes clients can ask for,
+// matching the REST limit.
+const maxListSize = 100
+
+type bookResolver struct {
+	book models.Book
+}
+
+func (r *bookResolver) ID() graphql.ID         { return toID(r.book.ID) }
+func (r *bookResolver) Title() string          { return r.book.Title }
+func (r *bookResolver) ISBN() string           { return r.book.ISBN }
+func (r *bookResolver) PublicationYear() int32 { return int32(r.book.PublicationYear) }
+func (r *bookResolver) Description() string    { return r.book.Description }
+func (r *bookResolver) CoverURL() *string {
+	if r.book.CoverKey == "" {
+		return nil
+	}
+	url := blob.URL(r.book.CoverKey)
+	return &url
+}
+
+func (r *bookResolver) Author(ctx context.Context) (*authorResolver, error) {
+	author, err := loadersFrom(ctx).authorByID.Load(ctx, r.book.AuthorID)()
+	if err != nil {
+		return nil, err
+	}
+	return &authorResolver{author: author}, nil
+}
+
+func (r *bookResolver) Reviews(ctx context.Context, args struct{ First int32 }) ([]*reviewResolver, error) {
+	reviews, err := loadersFrom(ctx).reviewsByBook.Load(ctx, r.book.ID)()
+	if err != nil {
+		return nil, err
+	}
+	n := listSize(args.First, len(reviews))
+	out := make([]*reviewResolver, 0, n)
+	for _, review := range reviews[:n] {
+		out = append(out, &reviewResolver{review: review})
+	}
+	return out, nil
+}
+
+type authorResolver struct {
+	author models.Author
+}
+
+func (r *authorResolver) ID() graphql.ID          { return toID(r.author.ID) }
+func (r *authorResolver) Name() string            { return r.author.Name }
+func (r *authorResolver) Biography() string       { return r.author.Biography }
+func (r *authorResolver) BirthDate() graphql.Time { return graphql.Time{Time: r.author.BirthDate} }
+
+func (r *authorResolver) Books(ctx context.Context, args struct{ First int32 }) ([]*bookResolver, error) {
+	books, err := loadersFrom(ctx).booksByAuthor.Load(ctx, r.author.ID)()
+	if err != nil {
+		return nil, err
+	}
+	n := listSize(args.First, len(books))
+	out := make([]*bookResolver, 0, n)
+	for _, book := range books[:n] {
+		out = append(out, &bookResolver{book: book})
+	}
+	return out, nil
+}
+
+type reviewResolver struct {
+	review models.Review
+}
+
+func (r *reviewResolver) ID() graphql.ID           { return toID(r.review.ID) }
+func (r *reviewResolver) Rating() int32            { return int32(r.review.Rating) }
+func (r *reviewResolver) Comment() string          { return r.review.Comment }
+func (r *reviewResolver) DatePosted() graphql.Time { return graphql.Time{Time: r.review.DatePosted} }
+func (r *reviewResolver) Status() string           { return r.review.Status }
+
+func (r *reviewResolver) Book(ctx context.Context) (*bookResolver, error) {
+	book, err := loadersFrom(ctx).bookByID.Load(ctx, r.review.BookID)()
+	if err != nil {
+		return nil, err
+	}
+	return &bookResolver{book: book}, nil
+}
+
+func toID(id uint) graphql.ID {
+	return graphql.ID(strconv.FormatUint(uint64(id), 10))
+}
+
+func fromID(id graphql.ID) (uint, error) {
+	n, err := strconv.ParseUint(string(id), 10, 64)
+	if err != nil || n == 0 {
+		return 0, fmt.Errorf("invalid ID %q", id)
+	}
+	return uint(n), nil
+}
+
+// listSize returns how many of available items to return for a first
+// argument, clamped to [0, maxListSize].
+func listSize(requested int32, available int) int {
+	n := int(requested)
+	if n < 0 {
+		n = 0
+	}
+	if n > maxListSize {
+		n = maxListSize
+	}
+	if n > available {
+		n = available
+	}
+	return n
+}
@@ -0,0 +1,76 @@
+package grpcserver
+
+import (
+	"context"
+
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/dto"
+	pb "github.com/MentalArts/go-rest-api-mehmet-pala/internal/pb/library/v1"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/services"
+)
+
+type authorServer struct {
+	pb.UnimplementedAuthorServiceServer
+}
+
+func (s *authorServer) GetAuthor(ctx context.Context, req *pb.GetAuthorRequest) (*pb.Author, error) {
+	author, err := services.GetAuthor(ctx, uint(req.GetId()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toPBAuthor(author), nil
+}
+
+func (s *authorServer) ListAuthors(ctx context.Context, req *pb.ListAuthorsRequest) (*pb.ListAuthorsResponse, error) {
+	// Same defaults and bounds as the REST pagination.
+	page, limit := int(req.GetPage()), int(req.GetLimit())
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+	authors, err := services.ListAuthors(ctx, (page-1)*limit, limit)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	resp := &pb.ListAuthorsResponse{Page: int32(page), Limit: int32(limit)}
+	for _, author := range authors {
+		resp.Authors = append(resp.Authors, toPBAuthor(author))
+	}
+	return resp, nil
+}
+
+func (s *authorServer) CreateAuthor(ctx context.Context, req *pb.CreateAuthorRequest) (*pb.Author, error) {
+	create := dto.CreateAuthorRequest{
+		Name:      req.GetName(),
+		Biography: req.GetBiography(),
+	}
+	if req.BirthDate != nil {
+		create.BirthDate = req.BirthDate.AsTime()
+	}
+	author, err := services.CreateAuthor(ctx, create)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toPBAuthor(author), nil
+}
+
+func (s *authorServer) UpdateAuthor(ctx context.Context, req *pb.UpdateAuthorRequest) (*pb.Author, error) {
+	author, err := services.UpdateAuthor(ctx, uint(req.GetId()), dto.UpdateAuthorRequest{
+		Name:      req.Name,
+		Biography: req.Biography,
+		BirthDate: fromTimestamp(req.BirthDate),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toPBAuthor(author), nil
+}
+
+func (s *authorServer) DeleteAuthor(ctx context.Context, req *pb.DeleteAuthorRequest) (*pb.Author, error) {
+	author, err := services.DeleteAuthor(ctx, uint(req.GetId()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toPBAuthor(author), nil
+}
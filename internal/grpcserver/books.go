@@ -0,0 +1,81 @@
+package grpcserver
+
+import (
+	"context"
+
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/dto"
+	pb "github.com/MentalArts/go-rest-api-mehmet-pala/internal/pb/library/v1"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/services"
+	"google.golang.org/grpc"
+)
+
+// listBooksBatchSize is how many rows ListBooks reads per query while streaming.
+const listBooksBatchSize = 100
+
+type bookServer struct {
+	pb.UnimplementedBookServiceServer
+}
+
+func (s *bookServer) GetBook(ctx context.Context, req *pb.GetBookRequest) (*pb.Book, error) {
+	book, err := services.GetBook(ctx, uint(req.GetId()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toPBBook(book), nil
+}
+
+func (s *bookServer) ListBooks(req *pb.ListBooksRequest, stream grpc.ServerStreamingServer[pb.Book]) error {
+	ctx := stream.Context()
+	var lastID uint
+	for {
+		books, err := services.BooksAfter(ctx, lastID, uint(req.GetAuthorId()), listBooksBatchSize)
+		if err != nil {
+			return toStatus(err)
+		}
+		for _, book := range books {
+			if err := stream.Send(toPBBook(book)); err != nil {
+				return err
+			}
+			lastID = book.ID
+		}
+		if len(books) < listBooksBatchSize {
+			return nil
+		}
+	}
+}
+
+func (s *bookServer) CreateBook(ctx context.Context, req *pb.CreateBookRequest) (*pb.Book, error) {
+	book, err := services.CreateBook(ctx, dto.CreateBookRequest{
+		Title:           req.GetTitle(),
+		AuthorID:        uint(req.GetAuthorId()),
+		ISBN:            req.GetIsbn(),
+		PublicationYear: int(req.GetPublicationYear()),
+		Description:     req.GetDescription(),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toPBBook(book), nil
+}
+
+func (s *bookServer) UpdateBook(ctx context.Context, req *pb.UpdateBookRequest) (*pb.Book, error) {
+	book, err := services.UpdateBook(ctx, uint(req.GetId()), dto.UpdateBookRequest{
+		Title:           req.Title,
+		AuthorID:        uintPtr(req.AuthorId),
+		ISBN:            req.Isbn,
+		PublicationYear: intPtr(req.PublicationYear),
+		Description:     req.Description,
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toPBBook(book), nil
+}
+
+func (s *bookServer) DeleteBook(ctx context.Context, req *pb.DeleteBookRequest) (*pb.Book, error) {
+	book, err := services.DeleteBook(ctx, uint(req.GetId()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toPBBook(book), nil
+}
@@ -0,0 +1,65 @@
+package grpcserver
+
+import (
+	"time"
+
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/models"
+	pb "github.com/MentalArts/go-rest-api-mehmet-pala/internal/pb/library/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func toPBBook(b models.Book) *pb.Book {
+	return &pb.Book{
+		Id:              uint64(b.ID),
+		Title:           b.Title,
+		AuthorId:        uint64(b.AuthorID),
+		Isbn:            b.ISBN,
+		PublicationYear: int32(b.PublicationYear),
+		Description:     b.Description,
+	}
+}
+
+func toPBAuthor(a models.Author) *pb.Author {
+	return &pb.Author{
+		Id:        uint64(a.ID),
+		Name:      a.Name,
+		Biography: a.Biography,
+		BirthDate: timestamppb.New(a.BirthDate),
+	}
+}
+
+func toPBReview(r models.Review) *pb.Review {
+	return &pb.Review{
+		Id:         uint64(r.ID),
+		BookId:     uint64(r.BookID),
+		Rating:     int32(r.Rating),
+		Comment:    r.Comment,
+		DatePosted: timestamppb.New(r.DatePosted),
+	}
+}
+
+// fromTimestamp returns nil for an unset timestamp, so optional update
+// fields are left unchanged.
+func fromTimestamp(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := ts.AsTime()
+	return &t
+}
+
+func uintPtr(v *uint64) *uint {
+	if v == nil {
+		return nil
+	}
+	u := uint(*v)
+	return &u
+}
+
+func intPtr(v *int32) *int {
+	if v == nil {
+		return nil
+	}
+	i := int(*v)
+	return &i
+}
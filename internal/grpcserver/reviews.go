@@ -0,0 +1,60 @@
+package grpcserver
+
+import (
+	"context"
+
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/dto"
+	pb "github.com/MentalArts/go-rest-api-mehmet-pala/internal/pb/library/v1"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/services"
+)
+
+type reviewServer struct {
+	pb.UnimplementedReviewServiceServer
+}
+
+func (s *reviewServer) ListReviews(ctx context.Context, req *pb.ListReviewsRequest) (*pb.ListReviewsResponse, error) {
+	reviews, err := services.ListReviews(ctx, uint(req.GetBookId()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	resp := &pb.ListReviewsResponse{}
+	for _, review := range reviews {
+		resp.Reviews = append(resp.Reviews, toPBReview(review))
+	}
+	return resp, nil
+}
+
+func (s *reviewServer) CreateReview(ctx context.Context, req *pb.CreateReviewRequest) (*pb.Review, error) {
+	create := dto.CreateReviewRequest{
+		Rating:  int(req.GetRating()),
+		Comment: req.GetComment(),
+	}
+	if req.DatePosted != nil {
+		create.DatePosted = req.DatePosted.AsTime()
+	}
+	review, err := services.CreateReview(ctx, uint(req.GetBookId()), create)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toPBReview(review), nil
+}
+
+func (s *reviewServer) UpdateReview(ctx context.Context, req *pb.UpdateReviewRequest) (*pb.Review, error) {
+	review, err := services.UpdateReview(ctx, uint(req.GetId()), dto.UpdateReviewRequest{
+		Rating:     intPtr(req.Rating),
+		Comment:    req.Comment,
+		DatePosted: fromTimestamp(req.DatePosted),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toPBReview(review), nil
+}
+
+func (s *reviewServer) DeleteReview(ctx context.Context, req *pb.DeleteReviewRequest) (*pb.Review, error) {
+	review, err := services.DeleteReview(ctx, uint(req.GetId()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toPBReview(review), nil
+}
@@ -0,0 +1,61 @@
+// Package grpcserver exposes the book, author and review services over gRPC,
+// sharing the services package with the REST and GraphQL APIs.
+package grpcserver
+
+//go:generate protoc -I ../../proto --go_out=../.. --go_opt=module=github.com/MentalArts/go-rest-api-mehmet-pala --go-grpc_out=../.. --go-grpc_opt=module=github.com/MentalArts/go-rest-api-mehmet-pala library/v1/library.proto
+
+import (
+	"context"
+	"errors"
+
+	pb "github.com/MentalArts/go-rest-api-mehmet-pala/internal/pb/library/v1"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/services"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+// New returns a gRPC server with the library services, the standard health
+// checking service and server reflection registered. The health server is
+// returned so callers can flip it to NOT_SERVING during shutdown. Every call
+// is tagged with an x-request-id and logged.
+func New() (*grpc.Server, *health.Server) {
+	srv := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(unaryLogging),
+		grpc.ChainStreamInterceptor(streamLogging),
+	)
+	pb.RegisterBookServiceServer(srv, &bookServer{})
+	pb.RegisterAuthorServiceServer(srv, &authorServer{})
+	pb.RegisterReviewServiceServer(srv, &reviewServer{})
+
+	healthSrv := health.NewServer()
+	healthpb.RegisterHealthServer(srv, healthSrv)
+	for name := range srv.GetServiceInfo() {
+		healthSrv.SetServingStatus(name, healthpb.HealthCheckResponse_SERVING)
+	}
+
+	reflection.Register(srv)
+	return srv, healthSrv
+}
+
+// toStatus maps errors from the services package to gRPC status codes.
+func toStatus(err error) error {
+	var validationErr *services.ValidationError
+	switch {
+	case errors.As(err, &validationErr), errors.Is(err, services.ErrInvalidAuthor):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, services.ErrBookNotFound),
+		errors.Is(err, services.ErrAuthorNotFound),
+		errors.Is(err, services.ErrReviewNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, services.ErrReviewFlagged):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return status.FromContextError(err).Err()
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
@@ -0,0 +1,247 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/blob"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/config"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/dto"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/i18n"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/models"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/services"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/tracing"
+	"github.com/gin-gonic/gin"
+)
+
+// maxAttachmentName is the longest file name kept for an attachment.
+const maxAttachmentName = 255
+
+// UploadAuthorAttachment godoc
+// @Summary Attach a file to an author
+// @Description Takes a JPEG, PNG, GIF or WebP image in the multipart field "file"; the type is detected from the content. Uploading a file the author already has returns the existing attachment with status 200.
+// @Tags authors
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Author ID"
+// @Param file formData file true "File to attach"
+// @Success 201 {object} dto.AttachmentResponse
+// @Success 200 {object} dto.AttachmentResponse
+// @Failure 413 {object} map[string]string
+// @Failure 415 {object} map[string]string
+// @Router /authors/{id}/attachments [post]
+func UploadAuthorAttachment(cfg config.AttachmentsConfig) gin.HandlerFunc {
+	return uploadAttachment(cfg, models.AttachmentOwnerAuthor, "author")
+}
+
+// UploadBookAttachment godoc
+// @Summary Attach a file to a book
+// @Description Takes a PDF, EPUB or JPEG, PNG, GIF or WebP image in the multipart field "file"; the type is detected from the content. Uploading a file the book already has returns the existing attachment with status 200.
+// @Tags books
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Book ID"
+// @Param file formData file true "File to attach"
+// @Success 201 {object} dto.AttachmentResponse
+// @Success 200 {object} dto.AttachmentResponse
+// @Failure 413 {object} map[string]string
+// @Failure 415 {object} map[string]string
+// @Router /books/{id}/attachments [post]
+func UploadBookAttachment(cfg config.AttachmentsConfig) gin.HandlerFunc {
+	return uploadAttachment(cfg, models.AttachmentOwnerBook, "book")
+}
+
+// uploadAttachment handles uploads for one owner type. Transfers may take
+// up to cfg.TransferTimeout instead of the usual request and connection
+// timeouts.
+func uploadAttachment(cfg config.AttachmentsConfig, ownerType, resource string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := pathID(c, resource)
+		if !ok {
+			return
+		}
+
+		setConnDeadline(c, time.Now().Add(cfg.TransferTimeout))
+		ctx, cancel := context.WithTimeout(c.Request.Context(), cfg.TransferTimeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		limit := int64(cfg.MaxBytes)
+		tooLarge := i18n.Messagef(c.Request.Context(), "Attachment must not exceed %d bytes", limit)
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit+multipartOverhead)
+		header, err := c.FormFile("file")
+		if err != nil {
+			var maxErr *http.MaxBytesError
+			if errors.As(err, &maxErr) {
+				c.JSON(http.StatusRequestEntityTooLarge, tracing.ErrorBody(c, tooLarge))
+			} else {
+				c.JSON(http.StatusBadRequest, tracing.ErrorBody(c, `A file is required in the "file" field`))
+			}
+			return
+		}
+		if header.Size > limit {
+			c.JSON(http.StatusRequestEntityTooLarge, tracing.ErrorBody(c, tooLarge))
+			return
+		}
+		file, err := header.Open()
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		defer file.Close()
+
+		att, created, err := services.CreateAttachment(ctx, cfg, services.AttachmentUpload{
+			OwnerType: ownerType,
+			OwnerID:   id,
+			Name:      attachmentName(header.Filename),
+			File:      file,
+		})
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		status := http.StatusOK
+		if created {
+			status = http.StatusCreated
+		}
+		c.JSON(status, gin.H{"data": dto.NewAttachmentResponse(att)})
+	}
+}
+
+// attachmentName strips any directories a client sent with the file name
+// and caps its length.
+func attachmentName(filename string) string {
+	name := filepath.Base(filepath.ToSlash(filename))
+	if name == "." || name == "/" {
+		name = "attachment"
+	}
+	if len(name) > maxAttachmentName {
+		name = name[:maxAttachmentName]
+	}
+	return name
+}
+
+// GetAuthorAttachments godoc
+// @Summary List an author's attachments
+// @Tags authors
+// @Produce json
+// @Param id path int true "Author ID"
+// @Success 200 {array} dto.AttachmentResponse
+// @Router /authors/{id}/attachments [get]
+func GetAuthorAttachments(c *gin.Context) {
+	listAttachments(c, models.AttachmentOwnerAuthor, "author")
+}
+
+// GetBookAttachments godoc
+// @Summary List a book's attachments
+// @Tags books
+// @Produce json
+// @Param id path int true "Book ID"
+// @Success 200 {array} dto.AttachmentResponse
+// @Router /books/{id}/attachments [get]
+func GetBookAttachments(c *gin.Context) {
+	listAttachments(c, models.AttachmentOwnerBook, "book")
+}
+
+func listAttachments(c *gin.Context, ownerType, resource string) {
+	id, ok := pathID(c, resource)
+	if !ok {
+		return
+	}
+
+	atts, err := services.ListAttachments(c.Request.Context(), ownerType, id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": dto.NewAttachmentResponses(atts)})
+}
+
+// GetAttachment godoc
+// @Summary Get an attachment's metadata
+// @Tags attachments
+// @Produce json
+// @Param id path int true "Attachment ID"
+// @Success 200 {object} dto.AttachmentResponse
+// @Router /attachments/{id} [get]
+func GetAttachment(c *gin.Context) {
+	id, ok := pathID(c, "attachment")
+	if !ok {
+		return
+	}
+
+	att, err := services.GetAttachment(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": dto.NewAttachmentResponse(att)})
+}
+
+// DownloadAttachment godoc
+// @Summary Download an attachment
+// @Description Supports range requests, and conditional requests with the ETag, which is the quoted SHA-256 checksum.
+// @Tags attachments
+// @Produce octet-stream
+// @Param id path int true "Attachment ID"
+// @Param Range header string false "Byte range to return"
+// @Success 200 {file} file
+// @Success 206 {file} file
+// @Router /attachments/{id}/download [get]
+func DownloadAttachment(cfg config.AttachmentsConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := pathID(c, "attachment")
+		if !ok {
+			return
+		}
+
+		att, err := services.GetAttachment(c.Request.Context(), id)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		obj, err := blob.Default.Open(c.Request.Context(), att.StorageKey)
+		if err != nil {
+			if errors.Is(err, blob.ErrNotFound) {
+				c.JSON(http.StatusNotFound, tracing.ErrorBody(c, "File not found"))
+			} else {
+				respondError(c, err)
+			}
+			return
+		}
+		defer obj.Close()
+
+		setConnDeadline(c, time.Now().Add(cfg.TransferTimeout))
+		c.Header("Content-Type", att.ContentType)
+		c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": att.Name}))
+		c.Header("ETag", `"`+att.Checksum+`"`)
+		c.Header("Cache-Control", "private, max-age=0, must-revalidate")
+		c.Header("X-Content-Type-Options", "nosniff")
+		http.ServeContent(c.Writer, c.Request, "", att.CreatedAt, obj)
+	}
+}
+
+// DeleteAttachment godoc
+// @Summary Delete an attachment
+// @Tags attachments
+// @Produce json
+// @Param id path int true "Attachment ID"
+// @Success 200 {object} dto.AttachmentResponse
+// @Router /attachments/{id} [delete]
+func DeleteAttachment(c *gin.Context) {
+	id, ok := pathID(c, "attachment")
+	if !ok {
+		return
+	}
+
+	att, err := services.DeleteAttachment(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": dto.NewAttachmentResponse(att)})
+}
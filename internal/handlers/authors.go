@@ -1,12 +1,18 @@
 package handlers
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/db"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/dto"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/i18n"
 	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/models"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/query"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/services"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/tracing"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
@@ -17,26 +23,38 @@ import (
 // @Produce json
 // @Param page query int false "Page number"
 // @Param limit query int false "Items per page"
-// @Success 200 {object} map[string]interface{}
+// @Param include query string false "Relations to embed: books, books.reviews"
+// @Param fields[authors] query string false "Comma-separated author fields to return"
+// @Param fields[books] query string false "Comma-separated book fields to return"
+// @Param fields[reviews] query string false "Comma-separated review fields to return"
+// @Success 200 {array} dto.AuthorResponse
 // @Router /authors [get]
 func GetAuthors(c *gin.Context) {
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
-	if page < 1 {
-		page = 1
-	}
-	if limit < 1 || limit > 100 {
-		limit = 10
+	page, limit, offset := paginate(c)
+
+	opts, err := query.Parse(c, "authors")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, tracing.ErrorBody(c, err.Error()))
+		return
 	}
-	offset := (page - 1) * limit
 
 	var authors []models.Author
-	result := db.DB.Offset(offset).Limit(limit).Find(&authors)
+	// Ordered like services.ListAuthors, so pages are stable across replicas and APIs
+	result := opts.Apply(db.Reader(c.Request.Context())).Order("authors.id").Offset(offset).Limit(limit).Find(&authors)
 	if result.Error != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": result.Error.Error()})
+		respondError(c, result.Error)
+		return
+	}
+	if err := services.LocalizeAuthors(c.Request.Context(), authors); err != nil {
+		respondError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": authors, "page": page, "limit": limit})
+	data, err := opts.Shape(dto.NewAuthorResponses(authors))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": data, "page": page, "limit": limit})
 }
 
 // GetAuthorByID godoc
@@ -44,20 +62,150 @@ func GetAuthors(c *gin.Context) {
 // @Tags authors
 // @Produce json
 // @Param id path int true "Author ID"
-// @Success 200 {object} models.Author
+// @Param include query string false "Relations to embed: books, books.reviews"
+// @Param fields[authors] query string false "Comma-separated author fields to return"
+// @Param fields[books] query string false "Comma-separated book fields to return"
+// @Param fields[reviews] query string false "Comma-separated review fields to return"
+// @Param with_stats query bool false "Include book count, publication year range and average rating"
+// @Success 200 {object} dto.AuthorResponse
 // @Router /authors/{id} [get]
 func GetAuthorByID(c *gin.Context) {
-	id := c.Param("id")
+	id, ok := pathID(c, "author")
+	if !ok {
+		return
+	}
+	opts, err := query.Parse(c, "authors")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, tracing.ErrorBody(c, err.Error()))
+		return
+	}
+
+	var author models.Author
+	if err := opts.Apply(db.Reader(c.Request.Context())).First(&author, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, tracing.ErrorBody(c, "Author not found"))
+		} else {
+			respondError(c, err)
+		}
+		return
+	}
+	if err := services.LocalizeAuthor(c.Request.Context(), &author); err != nil {
+		respondError(c, err)
+		return
+	}
+	resp := dto.NewAuthorResponse(author)
+	if withStats, _ := strconv.ParseBool(c.Query("with_stats")); withStats {
+		stats, err := authorStats(c.Request.Context(), author.ID)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		resp.Stats = stats
+	}
+	data, err := opts.Shape(resp)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": data})
+}
+
+// GetAuthorBooks godoc
+// @Summary List the books an author contributed to
+// @Tags authors
+// @Produce json
+// @Param id path int true "Author ID"
+// @Param role query string false "Only books the author is credited on with this role: author, editor, translator or illustrator"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Param sort query string false "Comma-separated sort fields, prefix with - for descending (default: publication_year)"
+// @Param include query string false "Relations to embed: author, reviews, genres, contributors"
+// @Param fields[books] query string false "Comma-separated book fields to return"
+// @Param fields[reviews] query string false "Comma-separated review fields to return"
+// @Success 200 {array} dto.BookResponse
+// @Router /authors/{id}/books [get]
+func GetAuthorBooks(c *gin.Context) {
+	id, ok := pathID(c, "author")
+	if !ok {
+		return
+	}
+	page, limit, offset := paginate(c)
+
+	opts, err := query.Parse(c, "books")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, tracing.ErrorBody(c, err.Error()))
+		return
+	}
+	order, err := query.ParseSort(c, "books", "publication_year ASC, id ASC")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, tracing.ErrorBody(c, err.Error()))
+		return
+	}
+
+	reader := db.Reader(c.Request.Context())
 	var author models.Author
-	if err := db.DB.First(&author, "id = ?", id).Error; err != nil {
+	if err := reader.First(&author, "id = ?", id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Author not found"})
+			c.JSON(http.StatusNotFound, tracing.ErrorBody(c, "Author not found"))
 		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			respondError(c, err)
+		}
+		return
+	}
+
+	credited := reader.Model(&models.BookContributor{}).Select("book_id").Where("author_id = ?", author.ID)
+	if role := c.Query("role"); role != "" {
+		switch role {
+		case models.RoleAuthor, models.RoleEditor, models.RoleTranslator, models.RoleIllustrator:
+		default:
+			c.JSON(http.StatusBadRequest, tracing.ErrorBody(c, i18n.Messagef(c.Request.Context(), "Unknown role %q", role)))
+			return
 		}
+		credited = credited.Where("role = ?", role)
+	}
+
+	var total int64
+	if err := reader.Model(&models.Book{}).Where("id IN (?)", credited).Count(&total).Error; err != nil {
+		respondError(c, err)
+		return
+	}
+
+	var books []models.Book
+	result := opts.Apply(reader).Where("id IN (?)", credited).
+		Order(order).Offset(offset).Limit(limit).Find(&books)
+	if result.Error != nil {
+		respondError(c, result.Error)
+		return
+	}
+	if err := services.LocalizeBooks(c.Request.Context(), books); err != nil {
+		respondError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": author})
+	data, err := opts.Shape(dto.NewBookResponses(books))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": data, "page": page, "limit": limit, "total": total})
+}
+
+// authorStats aggregates the books an author wrote or co-wrote and the
+// approved reviews across them.
+func authorStats(ctx context.Context, authorID uint) (*dto.AuthorStats, error) {
+	var stats dto.AuthorStats
+	err := db.Reader(ctx).Model(&models.Book{}).
+		Select(`COUNT(DISTINCT books.id) AS book_count,
+			MIN(books.publication_year) AS first_publication_year,
+			MAX(books.publication_year) AS last_publication_year,
+			AVG(reviews.rating) AS average_rating`).
+		Joins("LEFT JOIN reviews ON reviews.book_id = books.id AND reviews.status = ?", models.ReviewApproved).
+		Where("books.id IN (SELECT book_id FROM book_contributors WHERE author_id = ? AND role = ?)",
+			authorID, models.RoleAuthor).
+		Scan(&stats).Error
+	if err != nil {
+		return nil, err
+	}
+	return &stats, nil
 }
 
 // CreateAuthor godoc
@@ -65,21 +213,23 @@ func GetAuthorByID(c *gin.Context) {
 // @Tags authors
 // @Accept json
 // @Produce json
-// @Param author body models.Author true "Author to create"
-// @Success 201 {object} models.Author
+// @Param author body dto.CreateAuthorRequest true "Author to create"
+// @Success 201 {object} dto.AuthorResponse
 // @Router /authors [post]
 func CreateAuthor(c *gin.Context) {
-	var author models.Author
-	if err := c.ShouldBindJSON(&author); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	var req dto.CreateAuthorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, tracing.ErrorBody(c, i18n.Error(c.Request.Context(), err)))
 		return
 	}
-	result := db.DB.Create(&author)
-	if result.Error != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": result.Error.Error()})
+
+	author, err := services.CreateAuthor(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err)
 		return
 	}
-	c.JSON(http.StatusCreated, gin.H{"data": author})
+	resp := dto.NewAuthorResponse(author)
+	c.JSON(http.StatusCreated, gin.H{"data": resp})
 }
 
 // UpdateAuthor godoc
@@ -88,22 +238,28 @@ func CreateAuthor(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path int true "Author ID"
-// @Param author body models.Author true "Author data"
-// @Success 200 {object} models.Author
+// @Param author body dto.UpdateAuthorRequest true "Author data"
+// @Success 200 {object} dto.AuthorResponse
 // @Router /authors/{id} [put]
 func UpdateAuthor(c *gin.Context) {
-	id := c.Param("id")
-	var author models.Author
-	if err := db.DB.First(&author, id).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Author not found"})
+	id, ok := pathID(c, "author")
+	if !ok {
 		return
 	}
-	if err := c.ShouldBindJSON(&author); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+
+	var req dto.UpdateAuthorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, tracing.ErrorBody(c, i18n.Error(c.Request.Context(), err)))
+		return
+	}
+
+	author, err := services.UpdateAuthor(c.Request.Context(), id, req)
+	if err != nil {
+		respondError(c, err)
 		return
 	}
-	db.DB.Save(&author)
-	c.JSON(http.StatusOK, gin.H{"data": author})
+	resp := dto.NewAuthorResponse(author)
+	c.JSON(http.StatusOK, gin.H{"data": resp})
 }
 
 // DeleteAuthor godoc
@@ -114,12 +270,14 @@ func UpdateAuthor(c *gin.Context) {
 // @Success 200 {object} map[string]string
 // @Router /authors/{id} [delete]
 func DeleteAuthor(c *gin.Context) {
-	id := c.Param("id")
-	var author models.Author
-	if err := db.DB.First(&author, id).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Author not found"})
+	id, ok := pathID(c, "author")
+	if !ok {
+		return
+	}
+
+	if _, err := services.DeleteAuthor(c.Request.Context(), id); err != nil {
+		respondError(c, err)
 		return
 	}
-	db.DB.Delete(&author)
 	c.JSON(http.StatusOK, gin.H{"data": "Author deleted"})
 }
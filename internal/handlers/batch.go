@@ -0,0 +1,288 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"regexp"
+
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/db"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/dto"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/i18n"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/services"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/tracing"
+	"github.com/gin-gonic/gin"
+)
+
+// refPattern matches a reference to an earlier operation's result.
+var refPattern = regexp.MustCompile(`^\$([A-Za-z0-9_-]+)\.([A-Za-z0-9_]+)$`)
+
+// operationError is a problem with an operation itself, such as a missing
+// body or an unknown reference; it answers 400.
+type operationError struct {
+	msg string
+}
+
+func (e *operationError) Error() string { return e.msg }
+
+func operationErrorf(format string, args ...interface{}) error {
+	return &operationError{msg: fmt.Sprintf(format, args...)}
+}
+
+// ExecuteBatch godoc
+// @Summary Run several operations in one transaction
+// @Description Runs create, update and delete operations on authors, books and reviews in order, in a single transaction. Either all of them are applied or none. Later operations can refer to earlier results, e.g. "author_id": "$tolkien.id".
+// @Tags batch
+// @Accept json
+// @Produce json
+// @Param batch body dto.BatchRequest true "Operations to run"
+// @Success 200 {array} dto.BatchResult
+// @Router /batch [post]
+func ExecuteBatch(c *gin.Context) {
+	var req dto.BatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, tracing.ErrorBody(c, i18n.Error(c.Request.Context(), err)))
+		return
+	}
+
+	results := make([]dto.BatchResult, len(req.Operations))
+	seen := make(map[string]bool)
+	for i, op := range req.Operations {
+		if op.Ref != "" {
+			if seen[op.Ref] {
+				c.JSON(http.StatusBadRequest, tracing.ErrorBody(c, i18n.Messagef(c.Request.Context(), "Operation %d: ref %q is used more than once", i, op.Ref)))
+				return
+			}
+			seen[op.Ref] = true
+		}
+		results[i] = dto.BatchResult{Index: i, Ref: op.Ref, Status: http.StatusFailedDependency, Error: "Not executed"}
+	}
+
+	failed := -1
+	err := db.Transaction(c.Request.Context(), func(ctx context.Context) error {
+		refs := make(map[string]map[string]interface{})
+		for i, op := range req.Operations {
+			status, data, err := runOperation(ctx, op, refs)
+			if err != nil {
+				failed = i
+				return err
+			}
+			results[i] = dto.BatchResult{Index: i, Ref: op.Ref, Status: status, Data: data}
+			if op.Ref != "" {
+				if refs[op.Ref], err = fieldsOf(data); err != nil {
+					failed = i
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if failed < 0 {
+			// The commit itself failed
+			respondError(c, err)
+			return
+		}
+		status, message := batchErrorStatus(c, err)
+		if status == http.StatusInternalServerError {
+			slog.ErrorContext(c.Request.Context(), "batch operation failed", "operation", failed, "error", err)
+		}
+		for i := 0; i < failed; i++ {
+			results[i].Status, results[i].Data, results[i].Error = http.StatusFailedDependency, nil, "Rolled back"
+		}
+		results[failed].Status, results[failed].Error = status, message
+
+		body := tracing.ErrorBody(c, i18n.Messagef(c.Request.Context(), "Operation %d failed: %s", failed, message))
+		body["results"] = results
+		c.JSON(status, body)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": results})
+}
+
+// batchErrorStatus maps an operation's error like respondError does.
+func batchErrorStatus(c *gin.Context, err error) (int, string) {
+	var opErr *operationError
+	if errors.As(err, &opErr) {
+		return http.StatusBadRequest, opErr.msg
+	}
+	return localizedErrorStatus(c, err)
+}
+
+// runOperation executes op with the services package, returning the status
+// and data the single-resource endpoint would have answered.
+func runOperation(ctx context.Context, op dto.BatchOperation, refs map[string]map[string]interface{}) (int, interface{}, error) {
+	body, err := resolveRefs(op.Body, refs)
+	if err != nil {
+		return 0, nil, err
+	}
+	var id uint
+	if op.Action != "create" {
+		if id, err = resolveID(op.ID, refs); err != nil {
+			return 0, nil, err
+		}
+	}
+
+	switch op.Resource + " " + op.Action {
+	case "authors create":
+		var req dto.CreateAuthorRequest
+		if err := decodeBody(body, &req); err != nil {
+			return 0, nil, err
+		}
+		author, err := services.CreateAuthor(ctx, req)
+		return http.StatusCreated, dto.NewAuthorResponse(author), err
+	case "authors update":
+		var req dto.UpdateAuthorRequest
+		if err := decodeBody(body, &req); err != nil {
+			return 0, nil, err
+		}
+		author, err := services.UpdateAuthor(ctx, id, req)
+		return http.StatusOK, dto.NewAuthorResponse(author), err
+	case "authors delete":
+		author, err := services.DeleteAuthor(ctx, id)
+		return http.StatusOK, dto.NewAuthorResponse(author), err
+
+	case "books create":
+		var req dto.CreateBookRequest
+		if err := decodeBody(body, &req); err != nil {
+			return 0, nil, err
+		}
+		book, err := services.CreateBook(ctx, req)
+		return http.StatusCreated, dto.NewBookResponse(book), err
+	case "books update":
+		var req dto.UpdateBookRequest
+		if err := decodeBody(body, &req); err != nil {
+			return 0, nil, err
+		}
+		book, err := services.UpdateBook(ctx, id, req)
+		return http.StatusOK, dto.NewBookResponse(book), err
+	case "books delete":
+		book, err := services.DeleteBook(ctx, id)
+		return http.StatusOK, dto.NewBookResponse(book), err
+
+	case "reviews create":
+		var req struct {
+			dto.CreateReviewRequest
+			BookID uint `json:"book_id"`
+		}
+		if err := decodeBody(body, &req); err != nil {
+			return 0, nil, err
+		}
+		if req.BookID == 0 {
+			return 0, nil, operationErrorf("book_id is required to create a review")
+		}
+		review, err := services.CreateReview(ctx, req.BookID, req.CreateReviewRequest)
+		return http.StatusCreated, dto.NewReviewResponse(review), err
+	case "reviews update":
+		var req dto.UpdateReviewRequest
+		if err := decodeBody(body, &req); err != nil {
+			return 0, nil, err
+		}
+		review, err := services.UpdateReview(ctx, id, req)
+		return http.StatusOK, dto.NewReviewResponse(review), err
+	case "reviews delete":
+		review, err := services.DeleteReview(ctx, id)
+		return http.StatusOK, dto.NewReviewResponse(review), err
+	}
+	return 0, nil, operationErrorf("unsupported operation %s on %s", op.Action, op.Resource)
+}
+
+// resolveRefs replaces every "$<ref>.<field>" string in raw with the field
+// of the referenced result.
+func resolveRefs(raw json.RawMessage, refs map[string]map[string]interface{}) (json.RawMessage, error) {
+	if len(raw) == 0 {
+		return raw, nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, operationErrorf("invalid JSON: %v", err)
+	}
+	v, err := substitute(v, refs)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+func substitute(v interface{}, refs map[string]map[string]interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case string:
+		m := refPattern.FindStringSubmatch(v)
+		if m == nil {
+			return v, nil
+		}
+		result, ok := refs[m[1]]
+		if !ok {
+			return nil, operationErrorf("reference %s: no earlier operation has ref %q", v, m[1])
+		}
+		field, ok := result[m[2]]
+		if !ok {
+			return nil, operationErrorf("reference %s: result has no field %q", v, m[2])
+		}
+		return field, nil
+	case map[string]interface{}:
+		for k, item := range v {
+			resolved, err := substitute(item, refs)
+			if err != nil {
+				return nil, err
+			}
+			v[k] = resolved
+		}
+	case []interface{}:
+		for i, item := range v {
+			resolved, err := substitute(item, refs)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = resolved
+		}
+	}
+	return v, nil
+}
+
+// resolveID returns the target of an update or delete, given as a number or
+// a reference.
+func resolveID(raw json.RawMessage, refs map[string]map[string]interface{}) (uint, error) {
+	if len(raw) == 0 {
+		return 0, operationErrorf("id is required")
+	}
+	resolved, err := resolveRefs(raw, refs)
+	if err != nil {
+		return 0, err
+	}
+	var id uint
+	if err := json.Unmarshal(resolved, &id); err != nil || id == 0 {
+		return 0, operationErrorf("id must be a positive integer or a reference such as \"$ref.id\"")
+	}
+	return id, nil
+}
+
+// decodeBody decodes an operation's body into its request type.
+func decodeBody(body json.RawMessage, req interface{}) error {
+	if len(body) == 0 {
+		return operationErrorf("body is required")
+	}
+	if err := json.Unmarshal(body, req); err != nil {
+		return operationErrorf("invalid body: %v", err)
+	}
+	return nil
+}
+
+// fieldsOf returns the top-level fields of a result for later references.
+func fieldsOf(data interface{}) (map[string]interface{}, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var fields map[string]interface{}
+	err = dec.Decode(&fields)
+	return fields, err
+}
@@ -1,40 +1,80 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/db"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/dto"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/i18n"
 	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/models"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/query"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/services"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/tracing"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // GetBooks godoc
 // @Summary List all books
+// @Description Each book is a work, with its editions (hardcover, paperback, translations, ...) grouped under it.
 // @Tags books
 // @Produce json
 // @Param page query int false "Page number"
 // @Param limit query int false "Items per page"
-// @Success 200 {array} models.Book
+// @Param genre query string false "Only books in the genre with this slug"
+// @Param include_subgenres query bool false "With genre, also books in its subgenres at any depth"
+// @Param include query string false "Relations to embed: author, reviews, genres, contributors, editions, series, author.books, contributors.author, editions.publisher (default: author, editions)"
+// @Param fields[books] query string false "Comma-separated book fields to return"
+// @Param fields[authors] query string false "Comma-separated author fields to return"
+// @Param fields[reviews] query string false "Comma-separated review fields to return"
+// @Param fields[genres] query string false "Comma-separated genre fields to return"
+// @Param fields[contributors] query string false "Comma-separated contributor fields to return"
+// @Param fields[editions] query string false "Comma-separated edition fields to return"
+// @Success 200 {array} dto.BookResponse
 // @Router /books [get]
 func GetBooks(c *gin.Context) {
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
-	if page < 1 {
-		page = 1
+	page, limit, offset := paginate(c)
+
+	opts, err := query.Parse(c, "books", "author", "editions")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, tracing.ErrorBody(c, err.Error()))
+		return
 	}
-	if limit < 1 || limit > 100 {
-		limit = 10
+
+	tx := opts.Apply(db.Reader(c.Request.Context()))
+	if slug := c.Query("genre"); slug != "" {
+		subgenres, _ := strconv.ParseBool(c.Query("include_subgenres"))
+		genreIDs, err := services.GenreIDsBySlug(c.Request.Context(), slug, subgenres)
+		if err != nil {
+			if errors.Is(err, services.ErrGenreNotFound) {
+				c.JSON(http.StatusBadRequest, tracing.ErrorBody(c, i18n.Messagef(c.Request.Context(), "Unknown genre %q", slug)))
+			} else {
+				respondError(c, err)
+			}
+			return
+		}
+		tx = tx.Where("books.id IN (SELECT book_id FROM book_genres WHERE genre_id IN ?)", genreIDs)
 	}
-	offset := (page - 1) * limit
 
 	var books []models.Book
-	result := db.DB.Offset(offset).Limit(limit).Preload("Author").Find(&books)
+	// Ordered like services.ListBooks, so pages are stable across replicas and APIs
+	result := tx.Order("books.id").Offset(offset).Limit(limit).Find(&books)
 	if result.Error != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": result.Error.Error()})
+		respondError(c, result.Error)
+		return
+	}
+	if err := services.LocalizeBooks(c.Request.Context(), books); err != nil {
+		respondError(c, err)
+		return
+	}
+	data, err := opts.Shape(dto.NewBookResponses(books))
+	if err != nil {
+		respondError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": books, "page": page, "limit": limit})
+	c.JSON(http.StatusOK, gin.H{"data": data, "page": page, "limit": limit})
 }
 
 // GetBookByID godoc
@@ -42,17 +82,46 @@ func GetBooks(c *gin.Context) {
 // @Tags books
 // @Produce json
 // @Param id path int true "Book ID"
-// @Success 200 {object} models.Book
+// @Param include query string false "Relations to embed: author, reviews, genres, contributors, editions, series, author.books, contributors.author, editions.publisher (default: author, editions)"
+// @Param fields[books] query string false "Comma-separated book fields to return"
+// @Param fields[authors] query string false "Comma-separated author fields to return"
+// @Param fields[reviews] query string false "Comma-separated review fields to return"
+// @Param fields[genres] query string false "Comma-separated genre fields to return"
+// @Param fields[contributors] query string false "Comma-separated contributor fields to return"
+// @Param fields[editions] query string false "Comma-separated edition fields to return"
+// @Success 200 {object} dto.BookResponse
 // @Router /books/{id} [get]
 func GetBookByID(c *gin.Context) {
-	id := c.Param("id")
+	id, ok := pathID(c, "book")
+	if !ok {
+		return
+	}
+	opts, err := query.Parse(c, "books", "author", "editions")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, tracing.ErrorBody(c, err.Error()))
+		return
+	}
+
 	var book models.Book
-	result := db.DB.Preload("Author").First(&book, id)
+	result := opts.Apply(db.Reader(c.Request.Context())).First(&book, id)
 	if result.Error != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Book not found"})
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, tracing.ErrorBody(c, "Book not found"))
+		} else {
+			respondError(c, result.Error)
+		}
+		return
+	}
+	if err := services.LocalizeBook(c.Request.Context(), &book); err != nil {
+		respondError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": book})
+	data, err := opts.Shape(dto.NewBookResponse(book))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": data})
 }
 
 // CreateBook godoc
@@ -60,29 +129,24 @@ func GetBookByID(c *gin.Context) {
 // @Tags books
 // @Accept json
 // @Produce json
-// @Param book body models.Book true "Book to create"
-// @Success 201 {object} models.Book
+// @Param book body dto.CreateBookRequest true "Book to create"
+// @Param Idempotency-Key header string false "Unique key making retries of this request safe"
+// @Success 201 {object} dto.BookResponse
 // @Router /books [post]
 func CreateBook(c *gin.Context) {
-	var book models.Book
-	if err := c.ShouldBindJSON(&book); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	var req dto.CreateBookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, tracing.ErrorBody(c, i18n.Error(c.Request.Context(), err)))
 		return
 	}
 
-	// Validate AuthorID before inserting
-	var author models.Author
-	if err := db.DB.First(&author, book.AuthorID).Error; err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Author ID"})
-		return
-	}
-
-	result := db.DB.Create(&book)
-	if result.Error != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": result.Error.Error()})
+	book, err := services.CreateBook(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err)
 		return
 	}
-	c.JSON(http.StatusCreated, gin.H{"data": book})
+	resp := dto.NewBookResponse(book)
+	c.JSON(http.StatusCreated, gin.H{"data": resp})
 }
 
 // UpdateBook godoc
@@ -91,30 +155,28 @@ func CreateBook(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path int true "Book ID"
-// @Param book body models.Book true "Book data"
-// @Success 200 {object} models.Book
+// @Param book body dto.UpdateBookRequest true "Book data"
+// @Success 200 {object} dto.BookResponse
 // @Router /books/{id} [put]
 func UpdateBook(c *gin.Context) {
-	id := c.Param("id")
-	var book models.Book
-	if err := db.DB.First(&book, id).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Book not found"})
+	id, ok := pathID(c, "book")
+	if !ok {
 		return
 	}
 
-	var updatedBook models.Book
-	if err := c.ShouldBindJSON(&updatedBook); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	var req dto.UpdateBookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, tracing.ErrorBody(c, i18n.Error(c.Request.Context(), err)))
 		return
 	}
 
-	result := db.DB.Model(&book).Where("id = ?", id).Updates(updatedBook)
-	if result.Error != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": result.Error.Error()})
+	book, err := services.UpdateBook(c.Request.Context(), id, req)
+	if err != nil {
+		respondError(c, err)
 		return
 	}
-
-	c.JSON(http.StatusOK, gin.H{"data": book})
+	resp := dto.NewBookResponse(book)
+	c.JSON(http.StatusOK, gin.H{"data": resp})
 }
 
 // DeleteBook godoc
@@ -125,13 +187,16 @@ func UpdateBook(c *gin.Context) {
 // @Success 200 {object} map[string]interface{}
 // @Router /books/{id} [delete]
 func DeleteBook(c *gin.Context) {
-	id := c.Param("id")
-	var book models.Book
-	if err := db.DB.First(&book, id).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Book not found"})
+	id, ok := pathID(c, "book")
+	if !ok {
 		return
 	}
 
-	db.DB.Delete(&book)
-	c.JSON(http.StatusOK, gin.H{"message": "Book deleted", "book": book})
+	book, err := services.DeleteBook(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	resp := dto.NewBookResponse(book)
+	c.JSON(http.StatusOK, gin.H{"message": "Book deleted", "book": resp})
 }
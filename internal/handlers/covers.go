@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/blob"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/config"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/dto"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/i18n"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/services"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/tracing"
+	"github.com/gin-gonic/gin"
+)
+
+// multipartOverhead is the room left for the multipart framing around an
+// uploaded file when limiting the request body.
+const multipartOverhead = 64 << 10
+
+// UploadBookCover godoc
+// @Summary Upload a book's cover image
+// @Description Takes a JPEG, PNG, GIF or WebP image in the multipart field "cover"; the type is detected from the content. Small and medium thumbnails are generated and the previous cover is removed.
+// @Tags books
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Book ID"
+// @Param cover formData file true "Cover image"
+// @Success 200 {object} dto.BookResponse
+// @Failure 413 {object} map[string]string
+// @Failure 415 {object} map[string]string
+// @Router /books/{id}/cover [put]
+func UploadBookCover(cfg config.CoversConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := pathID(c, "book")
+		if !ok {
+			return
+		}
+
+		limit := int64(cfg.MaxBytes)
+		tooLarge := i18n.Messagef(c.Request.Context(), "Cover must not exceed %d bytes", limit)
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit+multipartOverhead)
+		header, err := c.FormFile("cover")
+		if err != nil {
+			var maxErr *http.MaxBytesError
+			if errors.As(err, &maxErr) {
+				c.JSON(http.StatusRequestEntityTooLarge, tracing.ErrorBody(c, tooLarge))
+			} else {
+				c.JSON(http.StatusBadRequest, tracing.ErrorBody(c, `A cover image is required in the "cover" field`))
+			}
+			return
+		}
+		if header.Size > limit {
+			c.JSON(http.StatusRequestEntityTooLarge, tracing.ErrorBody(c, tooLarge))
+			return
+		}
+		file, err := header.Open()
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		defer file.Close()
+		data, err := io.ReadAll(file)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+
+		book, err := services.SetBookCover(c.Request.Context(), id, data)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		resp := dto.NewBookResponse(book)
+		c.JSON(http.StatusOK, gin.H{"data": resp})
+	}
+}
+
+// DeleteBookCover godoc
+// @Summary Remove a book's cover image
+// @Tags books
+// @Produce json
+// @Param id path int true "Book ID"
+// @Success 200 {object} dto.BookResponse
+// @Router /books/{id}/cover [delete]
+func DeleteBookCover(c *gin.Context) {
+	id, ok := pathID(c, "book")
+	if !ok {
+		return
+	}
+
+	book, err := services.DeleteBookCover(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	resp := dto.NewBookResponse(book)
+	c.JSON(http.StatusOK, gin.H{"data": resp})
+}
+
+// ServeMedia streams a stored file, such as a cover image, from the blob
+// store. Keys are never reused, so responses may be cached indefinitely;
+// range requests are answered by http.ServeContent.
+func ServeMedia(c *gin.Context) {
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	obj, err := blob.Default.Open(c.Request.Context(), key)
+	if err != nil {
+		if errors.Is(err, blob.ErrNotFound) {
+			c.JSON(http.StatusNotFound, tracing.ErrorBody(c, "File not found"))
+		} else {
+			respondError(c, err)
+		}
+		return
+	}
+	defer obj.Close()
+
+	info := obj.Info()
+	c.Header("Content-Type", info.ContentType)
+	c.Header("Cache-Control", "public, max-age=31536000, immutable")
+	c.Header("X-Content-Type-Options", "nosniff")
+	http.ServeContent(c.Writer, c.Request, path.Base(key), info.ModTime, obj)
+}
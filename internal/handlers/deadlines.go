@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type responseControllerKey struct{}
+
+// WithResponseController makes an http.ResponseController for the
+// connection available to handlers. gin's writer does not expose the
+// underlying one, so streams and file transfers could not otherwise move
+// the server's read and write deadlines.
+func WithResponseController(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), responseControllerKey{}, http.NewResponseController(w))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// setConnDeadline replaces the connection's read and write deadlines, set
+// from HTTP_READ_TIMEOUT and HTTP_WRITE_TIMEOUT, with t; the zero time
+// removes them.
+func setConnDeadline(c *gin.Context, t time.Time) {
+	ctx := c.Request.Context()
+	rc, ok := ctx.Value(responseControllerKey{}).(*http.ResponseController)
+	if !ok {
+		return
+	}
+	if err := rc.SetReadDeadline(t); err != nil {
+		slog.WarnContext(ctx, "Could not change read deadline", "error", err)
+	}
+	if err := rc.SetWriteDeadline(t); err != nil {
+		slog.WarnContext(ctx, "Could not change write deadline", "error", err)
+	}
+}
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/dto"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/i18n"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/services"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/tracing"
+	"github.com/gin-gonic/gin"
+)
+
+// GetBookEditions godoc
+// @Summary List a book's editions
+// @Tags editions
+// @Produce json
+// @Param id path int true "Book ID"
+// @Success 200 {array} dto.EditionResponse
+// @Router /books/{id}/editions [get]
+func GetBookEditions(c *gin.Context) {
+	id, ok := pathID(c, "book")
+	if !ok {
+		return
+	}
+
+	editions, err := services.ListEditions(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": dto.NewEditionResponses(editions)})
+}
+
+// CreateEdition godoc
+// @Summary Add an edition to a book
+// @Tags editions
+// @Accept json
+// @Produce json
+// @Param id path int true "Book ID"
+// @Param edition body dto.CreateEditionRequest true "Edition to create"
+// @Success 201 {object} dto.EditionResponse
+// @Router /books/{id}/editions [post]
+func CreateEdition(c *gin.Context) {
+	id, ok := pathID(c, "book")
+	if !ok {
+		return
+	}
+
+	var req dto.CreateEditionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, tracing.ErrorBody(c, i18n.Error(c.Request.Context(), err)))
+		return
+	}
+
+	edition, err := services.CreateEdition(c.Request.Context(), id, req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": dto.NewEditionResponse(edition)})
+}
+
+// GetEditionByID godoc
+// @Summary Get a single edition by ID
+// @Tags editions
+// @Produce json
+// @Param id path int true "Edition ID"
+// @Success 200 {object} dto.EditionResponse
+// @Router /editions/{id} [get]
+func GetEditionByID(c *gin.Context) {
+	id, ok := pathID(c, "edition")
+	if !ok {
+		return
+	}
+
+	edition, err := services.GetEdition(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": dto.NewEditionResponse(edition)})
+}
+
+// UpdateEdition godoc
+// @Summary Update an existing edition
+// @Description Changing the ISBN of a book's first edition also changes the book's isbn.
+// @Tags editions
+// @Accept json
+// @Produce json
+// @Param id path int true "Edition ID"
+// @Param edition body dto.UpdateEditionRequest true "Edition data"
+// @Success 200 {object} dto.EditionResponse
+// @Router /editions/{id} [put]
+func UpdateEdition(c *gin.Context) {
+	id, ok := pathID(c, "edition")
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateEditionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, tracing.ErrorBody(c, i18n.Error(c.Request.Context(), err)))
+		return
+	}
+
+	edition, err := services.UpdateEdition(c.Request.Context(), id, req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": dto.NewEditionResponse(edition)})
+}
+
+// DeleteEdition godoc
+// @Summary Delete an edition
+// @Description A book's last edition cannot be deleted; delete the book instead.
+// @Tags editions
+// @Produce json
+// @Param id path int true "Edition ID"
+// @Success 200 {object} dto.EditionResponse
+// @Router /editions/{id} [delete]
+func DeleteEdition(c *gin.Context) {
+	id, ok := pathID(c, "edition")
+	if !ok {
+		return
+	}
+
+	edition, err := services.DeleteEdition(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": dto.NewEditionResponse(edition)})
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/attachments"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/covers"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/i18n"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/services"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/tracing"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/webhooks"
+	"github.com/gin-gonic/gin"
+)
+
+// respondError writes the response for an error returned by the services
+// package or by a query made with the request context. Queries cut short by
+// the request deadline answer 504 and cancelled requests 503.
+func respondError(c *gin.Context, err error) {
+	status, message := localizedErrorStatus(c, err)
+	if status == http.StatusInternalServerError {
+		slog.ErrorContext(c.Request.Context(), "request failed", "error", err)
+	}
+	c.JSON(status, tracing.ErrorBody(c, message))
+}
+
+// localizedErrorStatus is errorStatus with the message in the client's
+// language.
+func localizedErrorStatus(c *gin.Context, err error) (int, string) {
+	status, message := errorStatus(err)
+	var validationErr *services.ValidationError
+	if errors.As(err, &validationErr) {
+		return status, i18n.Error(c.Request.Context(), validationErr.Err)
+	}
+	var flaggedErr *services.ReviewFlaggedError
+	if errors.As(err, &flaggedErr) {
+		return status, i18n.Message(c.Request.Context(), services.ErrReviewFlagged.Error()) + ": " + flaggedErr.Reason
+	}
+	return status, i18n.Message(c.Request.Context(), message)
+}
+
+// errorStatus maps an error to the HTTP status and message respondError
+// uses for it.
+func errorStatus(err error) (int, string) {
+	var validationErr *services.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		return http.StatusBadRequest, err.Error()
+	case errors.Is(err, services.ErrBookNotFound):
+		return http.StatusNotFound, "Book not found"
+	case errors.Is(err, services.ErrAuthorNotFound):
+		return http.StatusNotFound, "Author not found"
+	case errors.Is(err, services.ErrReviewNotFound):
+		return http.StatusNotFound, "Review not found"
+	case errors.Is(err, services.ErrInvalidAuthor):
+		return http.StatusBadRequest, "Invalid Author ID"
+	case errors.Is(err, services.ErrReviewFlagged):
+		return http.StatusUnprocessableEntity, err.Error()
+	case errors.Is(err, covers.ErrUnsupportedType):
+		return http.StatusUnsupportedMediaType, err.Error()
+	case errors.Is(err, covers.ErrInvalidImage), errors.Is(err, covers.ErrTooManyPixels):
+		return http.StatusBadRequest, err.Error()
+	case errors.Is(err, services.ErrGenreNotFound):
+		return http.StatusNotFound, "Genre not found"
+	case errors.Is(err, services.ErrInvalidGenre):
+		return http.StatusBadRequest, err.Error()
+	case errors.Is(err, services.ErrGenreSlugTaken), errors.Is(err, services.ErrGenreHasSubgenres):
+		return http.StatusConflict, err.Error()
+	case errors.Is(err, services.ErrPublisherNotFound):
+		return http.StatusNotFound, "Publisher not found"
+	case errors.Is(err, services.ErrEditionNotFound):
+		return http.StatusNotFound, "Edition not found"
+	case errors.Is(err, services.ErrSeriesNotFound):
+		return http.StatusNotFound, "Series not found"
+	case errors.Is(err, services.ErrInvalidPublisher), errors.Is(err, services.ErrInvalidSeries):
+		return http.StatusBadRequest, err.Error()
+	case errors.Is(err, services.ErrPublisherNameTaken), errors.Is(err, services.ErrSeriesPositionTaken),
+		errors.Is(err, services.ErrLastEdition):
+		return http.StatusConflict, err.Error()
+	case errors.Is(err, services.ErrTranslationNotFound):
+		return http.StatusNotFound, "Translation not found"
+	case errors.Is(err, services.ErrAttachmentNotFound):
+		return http.StatusNotFound, "Attachment not found"
+	case errors.Is(err, attachments.ErrUnsupportedType):
+		return http.StatusUnsupportedMediaType, err.Error()
+	case errors.Is(err, services.ErrQuotaExceeded):
+		return http.StatusRequestEntityTooLarge, err.Error()
+	case errors.Is(err, webhooks.ErrSubscriptionNotFound):
+		return http.StatusNotFound, "Webhook not found"
+	case errors.Is(err, webhooks.ErrDeliveryNotFound):
+		return http.StatusNotFound, "Delivery not found"
+	case errors.Is(err, webhooks.ErrPrivateDestination):
+		return http.StatusBadRequest, err.Error()
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout, "Request timed out"
+	case errors.Is(err, context.Canceled):
+		return http.StatusServiceUnavailable, "Request cancelled"
+	default:
+		return http.StatusInternalServerError, err.Error()
+	}
+}
+
+// pathID parses the :id path parameter, responding with 400 when it is not
+// a positive integer.
+func pathID(c *gin.Context, resource string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, tracing.ErrorBody(c, "Invalid "+resource+" ID"))
+		return 0, false
+	}
+	return uint(id), true
+}
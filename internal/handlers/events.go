@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/config"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/events"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/outbox"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/services"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/tracing"
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+)
+
+// StreamEvents godoc
+// @Summary Stream catalog and review changes as Server-Sent Events
+// @Description Each event has the outbox event ID as its id, the event type (e.g. review.created) as its name and the resource as JSON data. Reconnecting with Last-Event-ID, or last_event_id in the query, first replays the events missed since.
+// @Tags events
+// @Produce text/event-stream
+// @Param types query string false "Comma-separated event types to receive (default: all)"
+// @Param Last-Event-ID header int false "ID of the last event received"
+// @Param last_event_id query int false "Same as Last-Event-ID, for clients that cannot set headers"
+// @Success 200 {string} string "text/event-stream"
+// @Router /events [get]
+func StreamEvents(cfg config.EventsConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var filter events.Filter
+		for _, t := range strings.Split(c.Query("types"), ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				filter.Types = append(filter.Types, t)
+			}
+		}
+		stream(c, cfg, filter)
+	}
+}
+
+// StreamBookReviews godoc
+// @Summary Stream a book's review activity as Server-Sent Events
+// @Description Sends review.created, review.updated and review.deleted events for the book, resuming from Last-Event-ID like /events.
+// @Tags reviews
+// @Produce text/event-stream
+// @Param id path int true "Book ID"
+// @Param Last-Event-ID header int false "ID of the last event received"
+// @Param last_event_id query int false "Same as Last-Event-ID, for clients that cannot set headers"
+// @Success 200 {string} string "text/event-stream"
+// @Router /books/{id}/reviews/stream [get]
+func StreamBookReviews(cfg config.EventsConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := pathID(c, "book")
+		if !ok {
+			return
+		}
+		if _, err := services.GetBook(c.Request.Context(), id); err != nil {
+			respondError(c, err)
+			return
+		}
+		stream(c, cfg, events.Filter{BookID: id})
+	}
+}
+
+// stream sends the events missed since Last-Event-ID, then live events
+// matching filter until the client goes away or the server shuts down.
+func stream(c *gin.Context, cfg config.EventsConfig, filter events.Filter) {
+	lastID, err := lastEventID(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, tracing.ErrorBody(c, "Invalid Last-Event-ID"))
+		return
+	}
+	ctx := c.Request.Context()
+
+	// Subscribe before reading the backlog so nothing falls in between
+	sub := events.Subscribe(filter)
+	defer events.Unsubscribe(sub)
+
+	var backlog []outbox.Message
+	if lastID > 0 && cfg.Backlog > 0 {
+		if backlog, err = events.Since(ctx, lastID, filter, cfg.Backlog); err != nil {
+			respondError(c, err)
+			return
+		}
+	}
+
+	// A stream outlives HTTP_READ_TIMEOUT and HTTP_WRITE_TIMEOUT
+	setConnDeadline(c, time.Time{})
+
+	header := c.Writer.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	header.Set("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	sent := make(map[uint]bool, len(backlog))
+	for _, msg := range backlog {
+		if err := writeEvent(c, msg); err != nil {
+			return
+		}
+		sent[msg.ID] = true
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(cfg.Heartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-sub.C:
+			if !ok {
+				return
+			}
+			if sent[msg.ID] {
+				continue
+			}
+			if err := writeEvent(c, msg); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := c.Writer.WriteString(": heartbeat\n\n"); err != nil {
+				return
+			}
+		}
+		c.Writer.Flush()
+	}
+}
+
+func writeEvent(c *gin.Context, msg outbox.Message) error {
+	return sse.Encode(c.Writer, sse.Event{
+		Id:    strconv.FormatUint(uint64(msg.ID), 10),
+		Event: msg.Type,
+		Data:  msg.Data,
+	})
+}
+
+// lastEventID returns the ID a reconnecting client last received, or 0.
+func lastEventID(c *gin.Context) (uint, error) {
+	raw := c.GetHeader("Last-Event-ID")
+	if raw == "" {
+		raw = c.Query("last_event_id")
+	}
+	if raw == "" {
+		return 0, nil
+	}
+	id, err := strconv.ParseUint(raw, 10, 64)
+	return uint(id), err
+}
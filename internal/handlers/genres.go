@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/dto"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/i18n"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/services"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/tracing"
+	"github.com/gin-gonic/gin"
+)
+
+// GetGenres godoc
+// @Summary List all genres
+// @Description Returns every genre ordered by name; parent_id links subgenres to their parent.
+// @Tags genres
+// @Produce json
+// @Success 200 {array} dto.GenreResponse
+// @Router /genres [get]
+func GetGenres(c *gin.Context) {
+	genres, err := services.ListGenres(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": dto.NewGenreResponses(genres)})
+}
+
+// GetGenreByID godoc
+// @Summary Get a single genre by ID, with its direct subgenres
+// @Tags genres
+// @Produce json
+// @Param id path int true "Genre ID"
+// @Success 200 {object} dto.GenreResponse
+// @Router /genres/{id} [get]
+func GetGenreByID(c *gin.Context) {
+	id, ok := pathID(c, "genre")
+	if !ok {
+		return
+	}
+
+	genre, err := services.GetGenreWithChildren(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": dto.NewGenreResponse(genre)})
+}
+
+// CreateGenre godoc
+// @Summary Create a new genre
+// @Tags genres
+// @Accept json
+// @Produce json
+// @Param genre body dto.CreateGenreRequest true "Genre to create"
+// @Success 201 {object} dto.GenreResponse
+// @Failure 409 {object} map[string]string
+// @Router /genres [post]
+func CreateGenre(c *gin.Context) {
+	var req dto.CreateGenreRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, tracing.ErrorBody(c, i18n.Error(c.Request.Context(), err)))
+		return
+	}
+
+	genre, err := services.CreateGenre(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": dto.NewGenreResponse(genre)})
+}
+
+// UpdateGenre godoc
+// @Summary Update an existing genre
+// @Description Setting parent_id moves the genre, with its subgenres, under another genre; 0 makes it a top-level genre.
+// @Tags genres
+// @Accept json
+// @Produce json
+// @Param id path int true "Genre ID"
+// @Param genre body dto.UpdateGenreRequest true "Genre data"
+// @Success 200 {object} dto.GenreResponse
+// @Failure 409 {object} map[string]string
+// @Router /genres/{id} [put]
+func UpdateGenre(c *gin.Context) {
+	id, ok := pathID(c, "genre")
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateGenreRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, tracing.ErrorBody(c, i18n.Error(c.Request.Context(), err)))
+		return
+	}
+
+	genre, err := services.UpdateGenre(c.Request.Context(), id, req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": dto.NewGenreResponse(genre)})
+}
+
+// DeleteGenre godoc
+// @Summary Delete a genre
+// @Description Books lose the genre. Genres with subgenres cannot be deleted.
+// @Tags genres
+// @Produce json
+// @Param id path int true "Genre ID"
+// @Success 200 {object} dto.GenreResponse
+// @Failure 409 {object} map[string]string
+// @Router /genres/{id} [delete]
+func DeleteGenre(c *gin.Context) {
+	id, ok := pathID(c, "genre")
+	if !ok {
+		return
+	}
+
+	genre, err := services.DeleteGenre(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": dto.NewGenreResponse(genre)})
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/dto"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/i18n"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/models"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/services"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/tracing"
+	"github.com/gin-gonic/gin"
+)
+
+// GetModerationQueue godoc
+// @Summary List reviews by moderation status
+// @Description Lists the reviews waiting for a moderator, oldest first, or those in another status.
+// @Tags moderation
+// @Produce json
+// @Param status query string false "pending (default), approved or rejected"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Param Authorization header string true "Bearer moderator token"
+// @Success 200 {array} dto.ModeratedReviewResponse
+// @Router /moderation/reviews [get]
+func GetModerationQueue(c *gin.Context) {
+	status := c.DefaultQuery("status", models.ReviewPending)
+	switch status {
+	case models.ReviewPending, models.ReviewApproved, models.ReviewRejected:
+	default:
+		c.JSON(http.StatusBadRequest, tracing.ErrorBody(c, i18n.Messagef(c.Request.Context(), "Unknown status %q", status)))
+		return
+	}
+	page, limit, offset := paginate(c)
+
+	reviews, total, err := services.ListReviewsByStatus(c.Request.Context(), status, offset, limit)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": dto.NewModeratedReviewResponses(reviews), "page": page, "limit": limit, "total": total})
+}
+
+// ModerateReview godoc
+// @Summary Approve or reject a review
+// @Description Approving a review shows it to everyone; rejecting an approved one hides it again.
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Param id path int true "Review ID"
+// @Param decision body dto.ModerateReviewRequest true "Moderator's decision"
+// @Param Authorization header string true "Bearer moderator token"
+// @Success 200 {object} dto.ModeratedReviewResponse
+// @Router /moderation/reviews/{id} [put]
+func ModerateReview(c *gin.Context) {
+	id, ok := pathID(c, "review")
+	if !ok {
+		return
+	}
+
+	var req dto.ModerateReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, tracing.ErrorBody(c, i18n.Error(c.Request.Context(), err)))
+		return
+	}
+
+	review, err := services.ModerateReview(c.Request.Context(), id, req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": dto.NewModeratedReviewResponse(review)})
+}
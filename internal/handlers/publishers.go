@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/dto"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/i18n"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/services"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/tracing"
+	"github.com/gin-gonic/gin"
+)
+
+// GetPublishers godoc
+// @Summary List all publishers
+// @Tags publishers
+// @Produce json
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {array} dto.PublisherResponse
+// @Router /publishers [get]
+func GetPublishers(c *gin.Context) {
+	page, limit, offset := paginate(c)
+
+	publishers, err := services.ListPublishers(c.Request.Context(), offset, limit)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": dto.NewPublisherResponses(publishers), "page": page, "limit": limit})
+}
+
+// GetPublisherByID godoc
+// @Summary Get a single publisher by ID
+// @Tags publishers
+// @Produce json
+// @Param id path int true "Publisher ID"
+// @Success 200 {object} dto.PublisherResponse
+// @Router /publishers/{id} [get]
+func GetPublisherByID(c *gin.Context) {
+	id, ok := pathID(c, "publisher")
+	if !ok {
+		return
+	}
+
+	publisher, err := services.GetPublisher(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": dto.NewPublisherResponse(publisher)})
+}
+
+// CreatePublisher godoc
+// @Summary Create a new publisher
+// @Tags publishers
+// @Accept json
+// @Produce json
+// @Param publisher body dto.CreatePublisherRequest true "Publisher to create"
+// @Success 201 {object} dto.PublisherResponse
+// @Failure 409 {object} map[string]string
+// @Router /publishers [post]
+func CreatePublisher(c *gin.Context) {
+	var req dto.CreatePublisherRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, tracing.ErrorBody(c, i18n.Error(c.Request.Context(), err)))
+		return
+	}
+
+	publisher, err := services.CreatePublisher(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": dto.NewPublisherResponse(publisher)})
+}
+
+// UpdatePublisher godoc
+// @Summary Update an existing publisher
+// @Tags publishers
+// @Accept json
+// @Produce json
+// @Param id path int true "Publisher ID"
+// @Param publisher body dto.UpdatePublisherRequest true "Publisher data"
+// @Success 200 {object} dto.PublisherResponse
+// @Failure 409 {object} map[string]string
+// @Router /publishers/{id} [put]
+func UpdatePublisher(c *gin.Context) {
+	id, ok := pathID(c, "publisher")
+	if !ok {
+		return
+	}
+
+	var req dto.UpdatePublisherRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, tracing.ErrorBody(c, i18n.Error(c.Request.Context(), err)))
+		return
+	}
+
+	publisher, err := services.UpdatePublisher(c.Request.Context(), id, req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": dto.NewPublisherResponse(publisher)})
+}
+
+// DeletePublisher godoc
+// @Summary Delete a publisher
+// @Description Its editions are kept without a publisher.
+// @Tags publishers
+// @Produce json
+// @Param id path int true "Publisher ID"
+// @Success 200 {object} dto.PublisherResponse
+// @Router /publishers/{id} [delete]
+func DeletePublisher(c *gin.Context) {
+	id, ok := pathID(c, "publisher")
+	if !ok {
+		return
+	}
+
+	publisher, err := services.DeletePublisher(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": dto.NewPublisherResponse(publisher)})
+}
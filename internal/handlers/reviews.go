@@ -3,117 +3,142 @@ package handlers
 import (
 	"errors"
 	"net/http"
-	"strconv"
 
 	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/db"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/dto"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/i18n"
 	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/models"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/moderation"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/query"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/services"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/tracing"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
 // GetReviewsForBook godoc
 // @Summary List all reviews for a specific book
+// @Description Only approved reviews are listed, except to moderators, who see all of them unless they filter by status.
 // @Tags reviews
 // @Produce json
 // @Param id path int true "Book ID"
-// @Success 200 {object} []models.Review
+// @Param status query string false "Moderators only: pending, approved or rejected"
+// @Param fields[reviews] query string false "Comma-separated review fields to return"
+// @Param Authorization header string false "Bearer moderator token"
+// @Success 200 {array} dto.ReviewResponse
 // @Router /books/{id}/reviews [get]
 func GetReviewsForBook(c *gin.Context) {
-	bookID := c.Param("id")
+	bookID, ok := pathID(c, "book")
+	if !ok {
+		return
+	}
+	opts, err := query.Parse(c, "reviews")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, tracing.ErrorBody(c, err.Error()))
+		return
+	}
 
 	// Check if book exists before fetching reviews
+	reader := db.Reader(c.Request.Context())
 	var book models.Book
-	if err := db.DB.First(&book, "id = ?", bookID).Error; err != nil {
+	if err := reader.First(&book, "id = ?", bookID).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Book not found"})
+			c.JSON(http.StatusNotFound, tracing.ErrorBody(c, "Book not found"))
 		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			respondError(c, err)
 		}
 		return
 	}
 
+	tx := opts.Apply(reader).Where("book_id = ?", bookID)
+	status := c.Query("status")
+	if !moderation.IsModerator(c) {
+		status = models.ReviewApproved
+	}
+	switch status {
+	case "":
+	case models.ReviewPending, models.ReviewApproved, models.ReviewRejected:
+		tx = tx.Where("status = ?", status)
+	default:
+		c.JSON(http.StatusBadRequest, tracing.ErrorBody(c, i18n.Messagef(c.Request.Context(), "Unknown status %q", status)))
+		return
+	}
+
 	var reviews []models.Review
-	result := db.DB.Where("book_id = ?", bookID).Find(&reviews)
+	result := tx.Find(&reviews)
 	if result.Error != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": result.Error.Error()})
+		respondError(c, result.Error)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": reviews})
+	data, err := opts.Shape(dto.NewReviewResponses(reviews))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": data})
 }
 
 // CreateReview godoc
 // @Summary Create a new review for a book
+// @Description The review is screened by the moderation filters: it is approved and shown at once, held for a moderator, or rejected, as its status says.
 // @Tags reviews
 // @Accept json
 // @Produce json
 // @Param id path int true "Book ID"
-// @Param review body models.Review true "Review to create"
-// @Success 201 {object} models.Review
+// @Param review body dto.CreateReviewRequest true "Review to create"
+// @Param Idempotency-Key header string false "Unique key making retries of this request safe"
+// @Success 201 {object} dto.ReviewResponse
 // @Router /books/{id}/reviews [post]
 func CreateReview(c *gin.Context) {
-	bookID := c.Param("id")
-	var review models.Review
-
-	if err := c.ShouldBindJSON(&review); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	var req dto.CreateReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, tracing.ErrorBody(c, i18n.Error(c.Request.Context(), err)))
 		return
 	}
 
 	// Validate Book ID
-	id, err := strconv.Atoi(bookID)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid book ID"})
-		return
-	}
-
-	// Check if book exists
-	var book models.Book
-	if err := db.DB.First(&book, "id = ?", id).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Book not found"})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		}
+	bookID, ok := pathID(c, "book")
+	if !ok {
 		return
 	}
 
-	review.BookID = uint(id)
-
-	// Create review
-	result := db.DB.Create(&review)
-	if result.Error != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": result.Error.Error()})
+	review, err := services.CreateReview(c.Request.Context(), bookID, req)
+	if err != nil {
+		respondError(c, err)
 		return
 	}
-	c.JSON(http.StatusCreated, gin.H{"data": review})
+	c.JSON(http.StatusCreated, gin.H{"data": dto.NewReviewResponse(review)})
 }
 
+// UpdateReview godoc
+// @Summary Update an existing review
+// @Description A new comment is screened again; approved reviews cannot be changed into text the filters would hold or reject.
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param id path int true "Review ID"
+// @Param review body dto.UpdateReviewRequest true "Review data"
+// @Success 200 {object} dto.ReviewResponse
+// @Router /reviews/{id} [put]
 func UpdateReview(c *gin.Context) {
-	id := c.Param("id")
-	var review models.Review
-
-	// Fetch review
-	if err := db.DB.First(&review, "id = ?", id).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Review not found"})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		}
+	id, ok := pathID(c, "review")
+	if !ok {
 		return
 	}
 
 	// Bind JSON request
-	if err := c.ShouldBindJSON(&review); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	var req dto.UpdateReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, tracing.ErrorBody(c, i18n.Error(c.Request.Context(), err)))
 		return
 	}
 
-	// Save updated review
-	if err := db.DB.Save(&review).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	review, err := services.UpdateReview(c.Request.Context(), id, req)
+	if err != nil {
+		respondError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": review})
+	c.JSON(http.StatusOK, gin.H{"data": dto.NewReviewResponse(review)})
 }
 
 // DeleteReview godoc
@@ -124,12 +149,14 @@ func UpdateReview(c *gin.Context) {
 // @Success 200 {object} map[string]string
 // @Router /reviews/{id} [delete]
 func DeleteReview(c *gin.Context) {
-	id := c.Param("id")
-	var review models.Review
-	if err := db.DB.First(&review, id).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Review not found"})
+	id, ok := pathID(c, "review")
+	if !ok {
+		return
+	}
+
+	if _, err := services.DeleteReview(c.Request.Context(), id); err != nil {
+		respondError(c, err)
 		return
 	}
-	db.DB.Delete(&review)
 	c.JSON(http.StatusOK, gin.H{"data": "Review deleted"})
 }
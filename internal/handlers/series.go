@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/dto"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/i18n"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/services"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/tracing"
+	"github.com/gin-gonic/gin"
+)
+
+// GetSeries godoc
+// @Summary List all series
+// @Tags series
+// @Produce json
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {array} dto.SeriesResponse
+// @Router /series [get]
+func GetSeries(c *gin.Context) {
+	page, limit, offset := paginate(c)
+
+	series, err := services.ListSeries(c.Request.Context(), offset, limit)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": dto.NewSeriesResponses(series), "page": page, "limit": limit})
+}
+
+// GetSeriesByID godoc
+// @Summary Get a single series by ID, with its books in order
+// @Tags series
+// @Produce json
+// @Param id path int true "Series ID"
+// @Success 200 {object} dto.SeriesResponse
+// @Router /series/{id} [get]
+func GetSeriesByID(c *gin.Context) {
+	id, ok := pathID(c, "series")
+	if !ok {
+		return
+	}
+
+	series, err := services.GetSeriesWithBooks(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if err := services.LocalizeBooks(c.Request.Context(), series.Books); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": dto.NewSeriesResponse(series)})
+}
+
+// CreateSeries godoc
+// @Summary Create a new series
+// @Description Books join a series through their series_id and series_position.
+// @Tags series
+// @Accept json
+// @Produce json
+// @Param series body dto.CreateSeriesRequest true "Series to create"
+// @Success 201 {object} dto.SeriesResponse
+// @Router /series [post]
+func CreateSeries(c *gin.Context) {
+	var req dto.CreateSeriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, tracing.ErrorBody(c, i18n.Error(c.Request.Context(), err)))
+		return
+	}
+
+	series, err := services.CreateSeries(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": dto.NewSeriesResponse(series)})
+}
+
+// UpdateSeries godoc
+// @Summary Update an existing series
+// @Tags series
+// @Accept json
+// @Produce json
+// @Param id path int true "Series ID"
+// @Param series body dto.UpdateSeriesRequest true "Series data"
+// @Success 200 {object} dto.SeriesResponse
+// @Router /series/{id} [put]
+func UpdateSeries(c *gin.Context) {
+	id, ok := pathID(c, "series")
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateSeriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, tracing.ErrorBody(c, i18n.Error(c.Request.Context(), err)))
+		return
+	}
+
+	series, err := services.UpdateSeries(c.Request.Context(), id, req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": dto.NewSeriesResponse(series)})
+}
+
+// DeleteSeries godoc
+// @Summary Delete a series
+// @Description Its books are kept, outside any series.
+// @Tags series
+// @Produce json
+// @Param id path int true "Series ID"
+// @Success 200 {object} dto.SeriesResponse
+// @Router /series/{id} [delete]
+func DeleteSeries(c *gin.Context) {
+	id, ok := pathID(c, "series")
+	if !ok {
+		return
+	}
+
+	series, err := services.DeleteSeries(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": dto.NewSeriesResponse(series)})
+}
@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/dto"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/i18n"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/services"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/tracing"
+	"github.com/gin-gonic/gin"
+)
+
+// GetBookTranslations godoc
+// @Summary List a book's translations
+// @Tags translations
+// @Produce json
+// @Param id path int true "Book ID"
+// @Success 200 {array} dto.BookTranslationResponse
+// @Router /books/{id}/translations [get]
+func GetBookTranslations(c *gin.Context) {
+	id, ok := pathID(c, "book")
+	if !ok {
+		return
+	}
+
+	translations, err := services.ListBookTranslations(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": dto.NewBookTranslationResponses(translations)})
+}
+
+// PutBookTranslation godoc
+// @Summary Set a book's title and description in a language
+// @Description The book's own title and description are in the default language; clients asking for this language through Accept-Language get the translation instead.
+// @Tags translations
+// @Accept json
+// @Produce json
+// @Param id path int true "Book ID"
+// @Param language path string true "BCP 47 language tag, e.g. tr or pt-BR"
+// @Param translation body dto.BookTranslationRequest true "Translated fields"
+// @Success 200 {object} dto.BookTranslationResponse
+// @Router /books/{id}/translations/{language} [put]
+func PutBookTranslation(c *gin.Context) {
+	id, ok := pathID(c, "book")
+	if !ok {
+		return
+	}
+
+	var req dto.BookTranslationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, tracing.ErrorBody(c, i18n.Error(c.Request.Context(), err)))
+		return
+	}
+
+	translation, err := services.PutBookTranslation(c.Request.Context(), id, c.Param("language"), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": dto.NewBookTranslationResponse(translation)})
+}
+
+// DeleteBookTranslation godoc
+// @Summary Delete a book's translation
+// @Tags translations
+// @Produce json
+// @Param id path int true "Book ID"
+// @Param language path string true "BCP 47 language tag"
+// @Success 200 {object} dto.BookTranslationResponse
+// @Router /books/{id}/translations/{language} [delete]
+func DeleteBookTranslation(c *gin.Context) {
+	id, ok := pathID(c, "book")
+	if !ok {
+		return
+	}
+
+	translation, err := services.DeleteBookTranslation(c.Request.Context(), id, c.Param("language"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": dto.NewBookTranslationResponse(translation)})
+}
+
+// GetAuthorTranslations godoc
+// @Summary List an author's translations
+// @Tags translations
+// @Produce json
+// @Param id path int true "Author ID"
+// @Success 200 {array} dto.AuthorTranslationResponse
+// @Router /authors/{id}/translations [get]
+func GetAuthorTranslations(c *gin.Context) {
+	id, ok := pathID(c, "author")
+	if !ok {
+		return
+	}
+
+	translations, err := services.ListAuthorTranslations(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": dto.NewAuthorTranslationResponses(translations)})
+}
+
+// PutAuthorTranslation godoc
+// @Summary Set an author's biography in a language
+// @Description The author's own biography is in the default language; clients asking for this language through Accept-Language get the translation instead.
+// @Tags translations
+// @Accept json
+// @Produce json
+// @Param id path int true "Author ID"
+// @Param language path string true "BCP 47 language tag, e.g. tr or pt-BR"
+// @Param translation body dto.AuthorTranslationRequest true "Translated fields"
+// @Success 200 {object} dto.AuthorTranslationResponse
+// @Router /authors/{id}/translations/{language} [put]
+func PutAuthorTranslation(c *gin.Context) {
+	id, ok := pathID(c, "author")
+	if !ok {
+		return
+	}
+
+	var req dto.AuthorTranslationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, tracing.ErrorBody(c, i18n.Error(c.Request.Context(), err)))
+		return
+	}
+
+	translation, err := services.PutAuthorTranslation(c.Request.Context(), id, c.Param("language"), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": dto.NewAuthorTranslationResponse(translation)})
+}
+
+// DeleteAuthorTranslation godoc
+// @Summary Delete an author's translation
+// @Tags translations
+// @Produce json
+// @Param id path int true "Author ID"
+// @Param language path string true "BCP 47 language tag"
+// @Success 200 {object} dto.AuthorTranslationResponse
+// @Router /authors/{id}/translations/{language} [delete]
+func DeleteAuthorTranslation(c *gin.Context) {
+	id, ok := pathID(c, "author")
+	if !ok {
+		return
+	}
+
+	translation, err := services.DeleteAuthorTranslation(c.Request.Context(), id, c.Param("language"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": dto.NewAuthorTranslationResponse(translation)})
+}
@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/dto"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/i18n"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/tracing"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/webhooks"
+	"github.com/gin-gonic/gin"
+)
+
+// GetWebhooks godoc
+// @Summary List webhook subscriptions
+// @Tags webhooks
+// @Produce json
+// @Param Authorization header string true "Bearer admin token"
+// @Success 200 {array} dto.WebhookResponse
+// @Router /webhooks [get]
+func GetWebhooks(c *gin.Context) {
+	subs, err := webhooks.ListSubscriptions(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": dto.NewWebhookResponses(subs)})
+}
+
+// GetWebhookByID godoc
+// @Summary Get a webhook subscription by ID
+// @Tags webhooks
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Param Authorization header string true "Bearer admin token"
+// @Success 200 {object} dto.WebhookResponse
+// @Router /webhooks/{id} [get]
+func GetWebhookByID(c *gin.Context) {
+	id, ok := pathID(c, "webhook")
+	if !ok {
+		return
+	}
+	sub, err := webhooks.GetSubscription(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": dto.NewWebhookResponse(sub)})
+}
+
+// CreateWebhook godoc
+// @Summary Subscribe a URL to events
+// @Description Deliveries are POSTed as JSON and signed in the X-Webhook-Signature header with HMAC-SHA256 of "<X-Webhook-Timestamp>.<body>" keyed with the secret. The secret is only returned in this response.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param webhook body dto.CreateWebhookRequest true "Subscription to create"
+// @Param Authorization header string true "Bearer admin token"
+// @Success 201 {object} dto.WebhookResponse
+// @Router /webhooks [post]
+func CreateWebhook(c *gin.Context) {
+	var req dto.CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, tracing.ErrorBody(c, i18n.Error(c.Request.Context(), err)))
+		return
+	}
+
+	sub, err := webhooks.CreateSubscription(c.Request.Context(), req.ToModel())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	resp := dto.NewWebhookResponse(sub)
+	resp.Secret = sub.Secret
+	c.JSON(http.StatusCreated, gin.H{"data": resp})
+}
+
+// UpdateWebhook godoc
+// @Summary Update a webhook subscription
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Param webhook body dto.UpdateWebhookRequest true "Subscription data"
+// @Param Authorization header string true "Bearer admin token"
+// @Success 200 {object} dto.WebhookResponse
+// @Router /webhooks/{id} [put]
+func UpdateWebhook(c *gin.Context) {
+	id, ok := pathID(c, "webhook")
+	if !ok {
+		return
+	}
+	var req dto.UpdateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, tracing.ErrorBody(c, i18n.Error(c.Request.Context(), err)))
+		return
+	}
+
+	sub, err := webhooks.UpdateSubscription(c.Request.Context(), id, req.Apply)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": dto.NewWebhookResponse(sub)})
+}
+
+// DeleteWebhook godoc
+// @Summary Delete a webhook subscription and its delivery log
+// @Tags webhooks
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Param Authorization header string true "Bearer admin token"
+// @Success 200 {object} map[string]string
+// @Router /webhooks/{id} [delete]
+func DeleteWebhook(c *gin.Context) {
+	id, ok := pathID(c, "webhook")
+	if !ok {
+		return
+	}
+	if err := webhooks.DeleteSubscription(c.Request.Context(), id); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": "Webhook deleted"})
+}
+
+// GetWebhookDeliveries godoc
+// @Summary List a subscription's deliveries, newest first
+// @Tags webhooks
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Param Authorization header string true "Bearer admin token"
+// @Success 200 {array} dto.WebhookDeliveryResponse
+// @Router /webhooks/{id}/deliveries [get]
+func GetWebhookDeliveries(c *gin.Context) {
+	id, ok := pathID(c, "webhook")
+	if !ok {
+		return
+	}
+	page, limit, offset := paginate(c)
+
+	ctx := c.Request.Context()
+	if _, err := webhooks.GetSubscription(ctx, id); err != nil {
+		respondError(c, err)
+		return
+	}
+	deliveries, total, err := webhooks.ListDeliveries(ctx, id, offset, limit)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": dto.NewWebhookDeliveryResponses(deliveries), "page": page, "limit": limit, "total": total})
+}
+
+// RedeliverWebhook godoc
+// @Summary Send a delivery again
+// @Description Queues a new delivery with the same event ID and payload; the original stays in the log.
+// @Tags webhooks
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Param delivery_id path int true "Delivery ID"
+// @Param Authorization header string true "Bearer admin token"
+// @Success 202 {object} dto.WebhookDeliveryResponse
+// @Router /webhooks/{id}/deliveries/{delivery_id}/redeliver [post]
+func RedeliverWebhook(c *gin.Context) {
+	id, ok := pathID(c, "webhook")
+	if !ok {
+		return
+	}
+	deliveryID, err := strconv.ParseUint(c.Param("delivery_id"), 10, 64)
+	if err != nil || deliveryID == 0 {
+		c.JSON(http.StatusBadRequest, tracing.ErrorBody(c, "Invalid delivery ID"))
+		return
+	}
+
+	delivery, err := webhooks.Redeliver(c.Request.Context(), id, uint(deliveryID))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"data": dto.NewWebhookDeliveryResponse(delivery)})
+}
@@ -0,0 +1,143 @@
+// Package health tracks whether the server should receive traffic and
+// checks the dependencies it needs to serve it.
+package health
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// CheckFunc pings a dependency, returning an error when it is unusable.
+type CheckFunc func(ctx context.Context) error
+
+type check struct {
+	name string
+	fn   CheckFunc
+}
+
+var (
+	ready   atomic.Bool
+	mu      sync.RWMutex
+	checks  []check
+	timeout = 2 * time.Second
+)
+
+// Prometheus gauges updated by every readiness probe.
+var (
+	DependencyUp = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "app_dependency_up",
+			Help: "Whether a dependency passed its last readiness check (1) or not (0)",
+		},
+		[]string{"dependency"},
+	)
+	DependencyLatency = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "app_dependency_check_duration_seconds",
+			Help: "Duration of the last readiness check of a dependency",
+		},
+		[]string{"dependency"},
+	)
+)
+
+// SetReady marks the server as ready or not ready for traffic. It is set to
+// false at the start of shutdown so load balancers stop routing new
+// requests while in-flight ones drain.
+func SetReady(v bool) {
+	ready.Store(v)
+}
+
+// Ready reports whether the server is accepting traffic.
+func Ready() bool {
+	return ready.Load()
+}
+
+// Register adds a dependency check run by the readiness probe.
+func Register(name string, fn CheckFunc) {
+	mu.Lock()
+	defer mu.Unlock()
+	checks = append(checks, check{name: name, fn: fn})
+}
+
+// SetTimeout sets how long each dependency check may take.
+func SetTimeout(d time.Duration) {
+	mu.Lock()
+	defer mu.Unlock()
+	timeout = d
+}
+
+// Result is the outcome of one dependency check.
+type Result struct {
+	Status    string  `json:"status"`
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// Run checks every registered dependency concurrently and records the
+// results in the Prometheus gauges.
+func Run(ctx context.Context) map[string]Result {
+	mu.RLock()
+	cs, d := checks, timeout
+	mu.RUnlock()
+
+	results := make(map[string]Result, len(cs))
+	var wg sync.WaitGroup
+	var resultsMu sync.Mutex
+	for _, c := range cs {
+		wg.Add(1)
+		go func(c check) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, d)
+			defer cancel()
+
+			start := time.Now()
+			err := c.fn(ctx)
+			elapsed := time.Since(start)
+
+			res := Result{Status: "up", LatencyMS: float64(elapsed.Microseconds()) / 1000}
+			up := 1.0
+			if err != nil {
+				res.Status, res.Error, up = "down", err.Error(), 0
+			}
+			DependencyUp.WithLabelValues(c.name).Set(up)
+			DependencyLatency.WithLabelValues(c.name).Set(elapsed.Seconds())
+
+			resultsMu.Lock()
+			results[c.name] = res
+			resultsMu.Unlock()
+		}(c)
+	}
+	wg.Wait()
+	return results
+}
+
+// Livez reports that the process is up and serving HTTP. It does not check
+// dependencies, so an outage elsewhere does not get the container restarted.
+func Livez(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "alive"})
+}
+
+// Readyz responds 200 when the server is ready and every dependency check
+// passes, and 503 with the per-dependency results otherwise.
+func Readyz(c *gin.Context) {
+	if !Ready() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "shutting down"})
+		return
+	}
+
+	results := Run(c.Request.Context())
+	status, code := "ready", http.StatusOK
+	for _, res := range results {
+		if res.Status != "up" {
+			status, code = "not ready", http.StatusServiceUnavailable
+			break
+		}
+	}
+	c.JSON(code, gin.H{"status": status, "checks": results})
+}
@@ -0,0 +1,141 @@
+// Package i18n picks the language of each response from the client's
+// Accept-Language header: which translation of the catalog's titles,
+// descriptions and biographies to serve, and which language error and
+// validation messages are written in.
+package i18n
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/config"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/text/language"
+)
+
+// maxPreferences bounds how many Accept-Language entries are considered.
+const maxPreferences = 10
+
+// defaultLanguage is the language of the catalog's own fields.
+var defaultLanguage = language.English
+
+// anyLanguage is what the Accept-Language wildcard parses to.
+var anyLanguage = language.MustParse("mul")
+
+var errUndetermined = errors.New("language is undetermined")
+
+type preferencesKey struct{}
+
+// Init sets the default language and registers the localized validation
+// messages with gin's validator.
+func Init(cfg config.I18nConfig) error {
+	tag, err := language.Parse(cfg.DefaultLanguage)
+	if err != nil {
+		return err
+	}
+	defaultLanguage = tag
+	return registerTranslations()
+}
+
+// DefaultLanguage returns the canonical tag of the language the catalog's
+// own fields are written in.
+func DefaultLanguage() string {
+	return defaultLanguage.String()
+}
+
+// Canonical parses a BCP 47 language tag and returns its canonical form,
+// e.g. "pt-BR" for "pt_br".
+func Canonical(lang string) (string, error) {
+	tag, err := language.Parse(lang)
+	if err != nil {
+		return "", err
+	}
+	if tag == language.Und {
+		return "", errUndetermined
+	}
+	return tag.String(), nil
+}
+
+// Middleware stores the languages the client accepts, most preferred
+// first, in the request context. Responses vary by Accept-Language.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Vary", "Accept-Language")
+		if prefs := parseAcceptLanguage(c.GetHeader("Accept-Language")); len(prefs) > 0 {
+			ctx := context.WithValue(c.Request.Context(), preferencesKey{}, prefs)
+			c.Request = c.Request.WithContext(ctx)
+		}
+		c.Next()
+	}
+}
+
+// parseAcceptLanguage returns the languages listed in an Accept-Language
+// header by decreasing quality, leaving out wildcards and rejected ones. A
+// malformed header counts as no preference.
+func parseAcceptLanguage(header string) []language.Tag {
+	if header == "" {
+		return nil
+	}
+	tags, q, err := language.ParseAcceptLanguage(header)
+	if err != nil {
+		return nil
+	}
+	var prefs []language.Tag
+	for i, tag := range tags {
+		if q[i] > 0 && tag != language.Und && tag != anyLanguage && len(prefs) < maxPreferences {
+			prefs = append(prefs, tag)
+		}
+	}
+	return prefs
+}
+
+// Preferred returns the languages the client accepts, most preferred first,
+// or nil when it did not say.
+func Preferred(ctx context.Context) []language.Tag {
+	prefs, _ := ctx.Value(preferencesKey{}).([]language.Tag)
+	return prefs
+}
+
+// Choose returns the language, among those a resource has translations
+// in, that the client prefers over the default language, or "" when the
+// resource's own fields suit it best. Each preference, in order, is matched
+// by the same tag or a more general one (pt for pt-BR), then by any tag of
+// the same language (pt-PT for pt-BR).
+func Choose(ctx context.Context, available []string) string {
+	prefs := Preferred(ctx)
+	if len(prefs) == 0 || len(available) == 0 {
+		return ""
+	}
+	sorted := append([]string(nil), available...)
+	sort.Strings(sorted)
+	has := make(map[string]bool, len(sorted))
+	for _, lang := range sorted {
+		has[lang] = true
+	}
+	defaultBase, _ := defaultLanguage.Base()
+
+	for _, pref := range prefs {
+		for tag := pref; ; tag = tag.Parent() {
+			if tag == defaultLanguage {
+				return ""
+			}
+			if has[tag.String()] {
+				return tag.String()
+			}
+			if tag.IsRoot() {
+				break
+			}
+		}
+		base, _ := pref.Base()
+		if base == defaultBase {
+			return ""
+		}
+		for _, lang := range sorted {
+			if b, _ := language.Make(lang).Base(); b == base {
+				return lang
+			}
+		}
+	}
+	return ""
+}
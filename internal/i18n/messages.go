@@ -0,0 +1,215 @@
+package i18n
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/tr"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	en_translations "github.com/go-playground/validator/v10/translations/en"
+	tr_translations "github.com/go-playground/validator/v10/translations/tr"
+	"golang.org/x/text/language"
+)
+
+// messageLanguages are the languages error and validation messages are
+// available in; English is the source language and the fallback.
+var messageLanguages = []language.Tag{language.English, language.Turkish}
+
+var messageMatcher = language.NewMatcher(messageLanguages)
+
+var translators = ut.New(en.New(), en.New(), tr.New())
+
+// catalog translates the API's English error messages, by language.
+var catalog = map[language.Tag]map[string]string{
+	language.Turkish: {
+		"Author not found":              "Yazar bulunamadı",
+		"Book not found":                "Kitap bulunamadı",
+		"Review not found":              "Yorum bulunamadı",
+		"Genre not found":               "Tür bulunamadı",
+		"Publisher not found":           "Yayınevi bulunamadı",
+		"Edition not found":             "Baskı bulunamadı",
+		"Series not found":              "Seri bulunamadı",
+		"Attachment not found":          "Ek bulunamadı",
+		"Translation not found":         "Çeviri bulunamadı",
+		"Webhook not found":             "Webhook bulunamadı",
+		"Delivery not found":            "Teslimat bulunamadı",
+		"File not found":                "Dosya bulunamadı",
+		"Invalid Author ID":             "Geçersiz yazar ID",
+		"Invalid author ID":             "Geçersiz yazar ID",
+		"Invalid book ID":               "Geçersiz kitap ID",
+		"Invalid review ID":             "Geçersiz yorum ID",
+		"Invalid genre ID":              "Geçersiz tür ID",
+		"Invalid publisher ID":          "Geçersiz yayınevi ID",
+		"Invalid edition ID":            "Geçersiz baskı ID",
+		"Invalid series ID":             "Geçersiz seri ID",
+		"Invalid attachment ID":         "Geçersiz ek ID",
+		"Invalid webhook ID":            "Geçersiz webhook ID",
+		"Invalid delivery ID":           "Geçersiz teslimat ID",
+		"Invalid Last-Event-ID":         "Geçersiz Last-Event-ID",
+		"Request timed out":             "İstek zaman aşımına uğradı",
+		"Request cancelled":             "İstek iptal edildi",
+		"Internal server error":         "Sunucu hatası",
+		"Rate limit exceeded":           "İstek sınırı aşıldı",
+		"Could not read request body":   "İstek gövdesi okunamadı",
+		"A moderator token is required": "Moderatör anahtarı gerekli",
+		"Only moderators may do this":   "Bunu yalnızca moderatörler yapabilir",
+		"An admin token is required":    "Yönetici anahtarı gerekli",
+		"Only admins may do this":       "Bunu yalnızca yöneticiler yapabilir",
+
+		"Idempotency-Key must be at most 255 characters":               "Idempotency-Key en fazla 255 karakter olabilir",
+		"Idempotency-Key was already used with a different request":    "Idempotency-Key farklı bir istekle zaten kullanıldı",
+		"A request with this Idempotency-Key is still being processed": "Bu Idempotency-Key ile gönderilen istek hâlâ işleniyor",
+
+		"invalid author ID":      "geçersiz yazar ID",
+		"invalid genre ID":       "geçersiz tür ID",
+		"invalid publisher ID":   "geçersiz yayınevi ID",
+		"invalid series ID":      "geçersiz seri ID",
+		"parent genre not found": "üst tür bulunamadı",
+
+		"at least one contributor must have the author role":                              "en az bir katkıda bulunanın rolü yazar olmalıdır",
+		"author_id must be the first contributor with the author role":                    "author_id, yazar rolündeki ilk katkıda bulunan olmalıdır",
+		"a genre cannot be placed under itself or one of its subgenres":                   "bir tür kendisinin ya da alt türlerinden birinin altına yerleştirilemez",
+		"another book already has this position in the series":                            "serideki bu sırada başka bir kitap var",
+		"another genre already has this slug":                                             "bu slug başka bir türe ait",
+		"a book keeps at least one edition; delete the book instead":                      "bir kitabın en az bir baskısı kalmalıdır; bunun yerine kitabı silin",
+		"another publisher already has this name":                                         "bu ad başka bir yayınevine ait",
+		"genre has subgenres; move or delete them first":                                  "türün alt türleri var; önce onları taşıyın ya da silin",
+		"slug may only contain lowercase letters, digits and single hyphens between them": "slug yalnızca küçük harf, rakam ve aralarında tek tire içerebilir",
+		"series_position is required when moving to another series":                       "başka bir seriye taşırken series_position zorunludur",
+		"series_position is required with series_id":                                      "series_id ile birlikte series_position zorunludur",
+		"series_position requires series_id":                                              "series_position için series_id gereklidir",
+		"cover must be a JPEG, PNG, GIF or WebP image":                                    "kapak JPEG, PNG, GIF ya da WebP görseli olmalıdır",
+		"cover image could not be decoded":                                                "kapak görseli çözülemedi",
+		"file type is not accepted for this resource":                                     "bu kaynak için dosya türü kabul edilmiyor",
+		"webhook URLs must point to a public host":                                        "webhook URL'leri herkese açık bir sunucuyu göstermelidir",
+		"storage quota exceeded":                                                          "depolama kotası aşıldı",
+		"the default language is stored on the resource itself":                           "varsayılan dil kaynağın kendisinde saklanır",
+		"approved reviews cannot be edited into text the moderation filters flag":         "onaylanmış yorumlar moderasyon filtrelerine takılan bir metinle düzenlenemez",
+		`A cover image is required in the "cover" field`:                                  `"cover" alanında bir kapak görseli gereklidir`,
+		`A file is required in the "file" field`:                                          `"file" alanında bir dosya gereklidir`,
+
+		// Formats for Messagef
+		"Unknown genre %q":                            "Bilinmeyen tür %q",
+		"Unknown status %q":                           "Bilinmeyen durum %q",
+		"Unknown role %q":                             "Bilinmeyen rol %q",
+		"Cover must not exceed %d bytes":              "Kapak en fazla %d bayt olabilir",
+		"Attachment must not exceed %d bytes":         "Ek en fazla %d bayt olabilir",
+		"Operation %d: ref %q is used more than once": "İşlem %d: ref %q birden fazla kez kullanılmış",
+		"Operation %d failed: %s":                     "İşlem %d başarısız oldu: %s",
+	},
+}
+
+// extraTranslations covers the validation tags the validator's bundled
+// translations lack, by language.
+var extraTranslations = map[string]map[string]string{
+	"en": {
+		"bcp47_language_tag": "{0} must be a BCP 47 language tag",
+		"startswith":         "{0} must start with '{1}'",
+	},
+	"tr": {
+		"required_without":   "{0} alanı, {1} verilmediğinde zorunludur",
+		"bcp47_language_tag": "{0} bir BCP 47 dil etiketi olmalıdır",
+		"startswith":         "{0}, '{1}' ile başlamalıdır",
+	},
+}
+
+// registerTranslations registers the validation messages of every message
+// language with gin's validator, and makes it name fields by their JSON
+// name as clients know them.
+func registerTranslations() error {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return errors.New("gin's validator is not go-playground/validator")
+	}
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return field.Name
+		}
+		return name
+	})
+
+	enTrans, _ := translators.GetTranslator("en")
+	if err := en_translations.RegisterDefaultTranslations(v, enTrans); err != nil {
+		return err
+	}
+	trTrans, _ := translators.GetTranslator("tr")
+	if err := tr_translations.RegisterDefaultTranslations(v, trTrans); err != nil {
+		return err
+	}
+	for locale, messages := range extraTranslations {
+		trans, _ := translators.GetTranslator(locale)
+		for tag, text := range messages {
+			if err := registerTranslation(v, trans, tag, text); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func registerTranslation(v *validator.Validate, trans ut.Translator, tag, text string) error {
+	return v.RegisterTranslation(tag, trans,
+		func(trans ut.Translator) error {
+			return trans.Add(tag, text, true)
+		},
+		func(trans ut.Translator, fe validator.FieldError) string {
+			msg, err := trans.T(tag, fe.Field(), fe.Param())
+			if err != nil {
+				return fe.Error()
+			}
+			return msg
+		})
+}
+
+// messageLanguage returns the language to write messages in: the one the
+// client prefers among those available, else the catalog's default
+// language if messages are available in it, else English.
+func messageLanguage(ctx context.Context) language.Tag {
+	prefs := append(append([]language.Tag(nil), Preferred(ctx)...), defaultLanguage)
+	_, i, confidence := messageMatcher.Match(prefs...)
+	if confidence == language.No {
+		return language.English
+	}
+	return messageLanguages[i]
+}
+
+// Message translates one of the API's English messages into the client's
+// language. Messages without a translation are returned unchanged.
+func Message(ctx context.Context, msg string) string {
+	if translated, ok := catalog[messageLanguage(ctx)][msg]; ok {
+		return translated
+	}
+	return msg
+}
+
+// Messagef translates format like Message, then fills in args, so messages
+// carrying values are found in the catalog by their format.
+func Messagef(ctx context.Context, format string, args ...interface{}) string {
+	return fmt.Sprintf(Message(ctx, format), args...)
+}
+
+// Error describes a request body error in the client's language: each
+// failed validation rule in a sentence, or the decoding error as it is.
+func Error(ctx context.Context, err error) string {
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		return err.Error()
+	}
+	base, _ := messageLanguage(ctx).Base()
+	trans, _ := translators.GetTranslator(base.String())
+	messages := make([]string, 0, len(fieldErrs))
+	for _, fe := range fieldErrs {
+		messages = append(messages, fe.Translate(trans))
+	}
+	return strings.Join(messages, "; ")
+}
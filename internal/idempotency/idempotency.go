@@ -0,0 +1,174 @@
+// Package idempotency lets clients safely retry POST requests. A request
+// carrying an Idempotency-Key header is executed once; retries with the same
+// key and body get the stored response back instead of creating duplicates.
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/auth"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/cache"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/config"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/tracing"
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	// Header is the request header carrying the client's idempotency key.
+	Header = "Idempotency-Key"
+	// ReplayedHeader is set to "true" on responses replayed from the store.
+	ReplayedHeader = "Idempotent-Replayed"
+
+	maxKeyLength = 255
+)
+
+// record is what is stored in Redis under a key. Status is zero while the
+// first request is still being processed.
+type record struct {
+	Fingerprint string `json:"fingerprint"`
+	Status      int    `json:"status,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+// Middleware makes the route idempotent for requests that send an
+// Idempotency-Key. Keys are scoped to the client, identified by its bearer
+// token or else its IP, and to the method and path, so clients cannot see or
+// collide with each other's keys. Within that scope the key is bound to a
+// fingerprint of the body: reusing it with a different request answers 422,
+// retrying while the first attempt is running answers 409. Responses below
+// 500 are stored for cfg.TTL; server errors, and requests the handler left
+// unanswered, release the key so the request can be retried.
+func Middleware(cfg config.IdempotencyConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(Header)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxKeyLength {
+			c.AbortWithStatusJSON(http.StatusBadRequest, tracing.ErrorBody(c, "Idempotency-Key must be at most 255 characters"))
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, tracing.ErrorBody(c, "Could not read request body"))
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := c.Request.Context()
+		redisKey := "idempotency:" + scopedKey(c, key)
+		rec := record{Fingerprint: fingerprint(c.Request.Method, c.Request.URL.Path, body)}
+
+		pending, _ := json.Marshal(rec)
+		acquired, err := cache.RDB.SetNX(ctx, redisKey, pending, cfg.LockTimeout).Result()
+		if err != nil {
+			slog.ErrorContext(ctx, "Error acquiring idempotency key", "error", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, tracing.ErrorBody(c, "Internal server error"))
+			return
+		}
+		if !acquired {
+			replay(c, redisKey, rec.Fingerprint)
+			return
+		}
+
+		w := &recorder{ResponseWriter: c.Writer}
+		c.Writer = w
+		c.Next()
+
+		// Store the outcome even if the client went away, so its retry
+		// is answered from the store.
+		storeCtx := context.WithoutCancel(ctx)
+		if !w.Written() || w.Status() >= http.StatusInternalServerError {
+			if err := cache.RDB.Del(storeCtx, redisKey).Err(); err != nil {
+				slog.WarnContext(ctx, "Error releasing idempotency key", "error", err)
+			}
+			return
+		}
+		rec.Status = w.Status()
+		rec.ContentType = w.Header().Get("Content-Type")
+		rec.Body = w.body.Bytes()
+		stored, _ := json.Marshal(rec)
+		if err := cache.RDB.Set(storeCtx, redisKey, stored, cfg.TTL).Err(); err != nil {
+			slog.WarnContext(ctx, "Error storing idempotent response", "error", err)
+		}
+	}
+}
+
+// replay answers a request whose key is already taken.
+func replay(c *gin.Context, redisKey, fp string) {
+	ctx := c.Request.Context()
+	raw, err := cache.RDB.Get(ctx, redisKey).Bytes()
+	if errors.Is(err, redis.Nil) {
+		// The first attempt failed and released the key in between.
+		c.AbortWithStatusJSON(http.StatusConflict, tracing.ErrorBody(c, "A request with this Idempotency-Key is still being processed"))
+		return
+	}
+	var rec record
+	if err == nil {
+		err = json.Unmarshal(raw, &rec)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "Error reading idempotency key", "error", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, tracing.ErrorBody(c, "Internal server error"))
+		return
+	}
+
+	switch {
+	case rec.Fingerprint != fp:
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, tracing.ErrorBody(c, "Idempotency-Key was already used with a different request"))
+	case rec.Status == 0:
+		c.AbortWithStatusJSON(http.StatusConflict, tracing.ErrorBody(c, "A request with this Idempotency-Key is still being processed"))
+	default:
+		c.Header(ReplayedHeader, "true")
+		c.Data(rec.Status, rec.ContentType, rec.Body)
+		c.Abort()
+	}
+}
+
+// scopedKey hashes the client's key together with the client's identity and
+// the request's method and path.
+func scopedKey(c *gin.Context, key string) string {
+	client := "ip:" + c.ClientIP()
+	if token, ok := auth.BearerToken(c); ok {
+		client = "token:" + token
+	}
+	h := sha256.New()
+	io.WriteString(h, client+"\n"+c.Request.Method+" "+c.Request.URL.Path+"\n"+key)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// fingerprint identifies a request by method, path and body.
+func fingerprint(method, path string, body []byte) string {
+	h := sha256.New()
+	io.WriteString(h, method+" "+path+"\n")
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// recorder keeps a copy of the response body.
+type recorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *recorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,64 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// RedisHook logs Redis commands with the caller's context, so they carry
+// the request ID. Failed commands are logged at error and the rest at debug;
+// redis.Nil (a missing key) is not a failure.
+type RedisHook struct{}
+
+type redisStartKey struct{}
+
+func (RedisHook) BeforeProcess(ctx context.Context, cmd redis.Cmder) (context.Context, error) {
+	return context.WithValue(ctx, redisStartKey{}, time.Now()), nil
+}
+
+func (RedisHook) AfterProcess(ctx context.Context, cmd redis.Cmder) error {
+	logRedis(ctx, cmd.Name(), cmd.Err())
+	return nil
+}
+
+func (RedisHook) BeforeProcessPipeline(ctx context.Context, cmds []redis.Cmder) (context.Context, error) {
+	return context.WithValue(ctx, redisStartKey{}, time.Now()), nil
+}
+
+func (RedisHook) AfterProcessPipeline(ctx context.Context, cmds []redis.Cmder) error {
+	var err error
+	for _, cmd := range cmds {
+		if cmdErr := cmd.Err(); cmdErr != nil && !errors.Is(cmdErr, redis.Nil) {
+			err = cmdErr
+			break
+		}
+	}
+	logRedis(ctx, "pipeline", err)
+	return nil
+}
+
+func logRedis(ctx context.Context, name string, err error) {
+	level, msg := slog.LevelDebug, "redis command"
+	if err != nil && !errors.Is(err, redis.Nil) {
+		level, msg = slog.LevelError, "redis command failed"
+	}
+	if !slog.Default().Enabled(ctx, level) {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("component", "redis"),
+		slog.String("command", name),
+	}
+	if start, ok := ctx.Value(redisStartKey{}).(time.Time); ok {
+		attrs = append(attrs, slog.Float64("duration_ms", Millis(time.Since(start))))
+	}
+	if level == slog.LevelError {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	slog.LogAttrs(ctx, level, msg, attrs...)
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// Owner types of attachments, as stored by GORM's polymorphic associations.
+const (
+	AttachmentOwnerAuthor = "authors"
+	AttachmentOwnerBook   = "books"
+)
+
+// Attachment is a file, such as an author portrait or a sample chapter,
+// attached to an author or a book. Identical files share one stored object,
+// found by Checksum.
+type Attachment struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	OwnerType   string    `gorm:"size:20;not null;index:idx_attachments_owner" json:"owner_type"`
+	OwnerID     uint      `gorm:"not null;index:idx_attachments_owner" json:"owner_id"`
+	Name        string    `gorm:"size:255;not null" json:"name"`
+	ContentType string    `gorm:"size:100;not null" json:"content_type"`
+	Size        int64     `gorm:"not null" json:"size"`
+	Checksum    string    `gorm:"size:64;not null;index" json:"checksum"`
+	StorageKey  string    `gorm:"size:255;not null" json:"-"`
+	CreatedAt   time.Time `json:"created_at"`
+}
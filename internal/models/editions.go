@@ -0,0 +1,39 @@
+package models
+
+// Edition formats.
+const (
+	FormatHardcover = "hardcover"
+	FormatPaperback = "paperback"
+	FormatEbook     = "ebook"
+	FormatAudiobook = "audiobook"
+)
+
+// Publisher publishes editions of books.
+type Publisher struct {
+	ID       uint      `gorm:"primaryKey" json:"id"`
+	Name     string    `gorm:"size:255;not null;uniqueIndex" json:"name"`
+	Website  string    `gorm:"size:255" json:"website"`
+	Editions []Edition `gorm:"foreignKey:PublisherID" json:"editions,omitempty"`
+}
+
+// Edition is one published form of a book, the work: a hardcover, a
+// paperback, a translation, ... Format and Language are empty for editions
+// migrated from books created before editions existed.
+type Edition struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	BookID      uint      `gorm:"not null;index" json:"book_id"`
+	PublisherID *uint     `gorm:"index" json:"publisher_id"`
+	ISBN        string    `gorm:"size:20;not null;index" json:"isbn"`
+	Format      string    `gorm:"size:20" json:"format"`
+	PageCount   int       `json:"page_count"`
+	Language    string    `gorm:"size:35" json:"language"`
+	Publisher   Publisher `gorm:"foreignKey:PublisherID" json:"publisher,omitempty"`
+}
+
+// Series is an ordered sequence of books, such as a trilogy.
+type Series struct {
+	ID          uint   `gorm:"primaryKey" json:"id"`
+	Name        string `gorm:"size:255;not null" json:"name"`
+	Description string `json:"description"`
+	Books       []Book `gorm:"foreignKey:SeriesID" json:"books,omitempty"`
+}
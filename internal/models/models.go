@@ -6,27 +6,96 @@ import (
 
 type Author struct {
 	ID        uint      `gorm:"primaryKey" json:"id"`
-	Name      string    `json:"name" binding:"required"`
+	Name      string    `json:"name"`
 	Biography string    `json:"biography"`
-	BirthDate time.Time `json:"birth_date" binding:"required"`
+	BirthDate time.Time `json:"birth_date"`
 	Books     []Book    `json:"books,omitempty"`
+
+	// Language is the language Biography was served in, set when the
+	// author is localized for a request.
+	Language string `gorm:"-" json:"language,omitempty"`
+
+	Attachments []Attachment `gorm:"polymorphic:Owner" json:"attachments,omitempty"`
 }
 
+// Book is a work in the catalog, published in one or more Editions.
+// AuthorID is its primary author, the first contributor with the author
+// role; Contributors lists everyone credited, in order. ISBN is the ISBN of
+// its first edition.
 type Book struct {
 	ID              uint     `gorm:"primaryKey" json:"id"`
-	Title           string   `json:"title" binding:"required"`
-	AuthorID        uint     `json:"author_id" binding:"required"`
-	ISBN            string   `json:"isbn" binding:"required"`
-	PublicationYear int      `json:"publication_year" binding:"required"`
+	Title           string   `json:"title"`
+	AuthorID        uint     `json:"author_id"`
+	ISBN            string   `json:"isbn"`
+	PublicationYear int      `json:"publication_year"`
 	Description     string   `json:"description"`
+	CoverKey        string   `gorm:"size:255" json:"-"`
+	SeriesID        *uint    `gorm:"uniqueIndex:idx_books_series_position" json:"series_id"`
+	SeriesPosition  *int     `gorm:"uniqueIndex:idx_books_series_position" json:"series_position"`
 	Author          Author   `gorm:"foreignKey:AuthorID" json:"author,omitempty"`
 	Reviews         []Review `gorm:"foreignKey:BookID" json:"reviews,omitempty"`
+	Genres          []Genre  `gorm:"many2many:book_genres;constraint:OnDelete:CASCADE" json:"genres,omitempty"`
+
+	Contributors []BookContributor `gorm:"foreignKey:BookID" json:"contributors,omitempty"`
+	Editions     []Edition         `gorm:"foreignKey:BookID" json:"editions,omitempty"`
+	Series       *Series           `gorm:"foreignKey:SeriesID" json:"series,omitempty"`
+
+	// Language is the language Title and Description were served in, set
+	// when the book is localized for a request.
+	Language string `gorm:"-" json:"language,omitempty"`
+
+	Attachments []Attachment `gorm:"polymorphic:Owner" json:"attachments,omitempty"`
+}
+
+// Contributor roles.
+const (
+	RoleAuthor      = "author"
+	RoleEditor      = "editor"
+	RoleTranslator  = "translator"
+	RoleIllustrator = "illustrator"
+)
+
+// BookContributor credits an author with a role on a book. Position orders
+// a book's contributors as they appear in its credits.
+type BookContributor struct {
+	BookID   uint   `gorm:"primaryKey" json:"book_id"`
+	AuthorID uint   `gorm:"primaryKey;index" json:"author_id"`
+	Role     string `gorm:"primaryKey;size:20" json:"role"`
+	Position int    `gorm:"not null" json:"position"`
+	Author   Author `gorm:"foreignKey:AuthorID;constraint:OnDelete:CASCADE" json:"author,omitempty"`
 }
 
+// Review moderation statuses. Only approved reviews are shown publicly.
+const (
+	ReviewPending  = "pending"
+	ReviewApproved = "approved"
+	ReviewRejected = "rejected"
+)
+
+// Review is a reader's rating of a book. Status is set by the moderation
+// filters when it is posted and by moderators afterwards, with
+// ModerationReason saying why it was held or rejected. CommentHash
+// fingerprints the comment to find duplicates.
 type Review struct {
 	ID         uint      `gorm:"primaryKey" json:"id"`
-	BookID     uint      `json:"book_id" binding:"required"`
-	Rating     int       `json:"rating" binding:"required,min=1,max=5"`
-	Comment    string    `json:"comment" binding:"required"`
-	DatePosted time.Time `json:"date_posted" binding:"required"`
+	BookID     uint      `json:"book_id"`
+	Rating     int       `json:"rating"`
+	Comment    string    `json:"comment"`
+	DatePosted time.Time `json:"date_posted"`
+
+	Status           string    `gorm:"size:20;not null;default:'approved';index" json:"status"`
+	ModerationReason string    `json:"moderation_reason,omitempty"`
+	CommentHash      string    `gorm:"size:64;index" json:"-"`
+	CreatedAt        time.Time `gorm:"not null;default:now()" json:"-"`
+}
+
+// Genre is a category books are classified under. Genres form a tree
+// through ParentID, e.g. "High Fantasy" under "Fantasy".
+type Genre struct {
+	ID          uint    `gorm:"primaryKey" json:"id"`
+	Name        string  `gorm:"size:100;not null" json:"name"`
+	Slug        string  `gorm:"size:100;not null;uniqueIndex" json:"slug"`
+	Description string  `json:"description"`
+	ParentID    *uint   `gorm:"index" json:"parent_id"`
+	Children    []Genre `gorm:"foreignKey:ParentID;constraint:OnDelete:RESTRICT" json:"children,omitempty"`
 }
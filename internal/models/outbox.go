@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// OutboxEvent is a domain event recorded in the same transaction as the
+// change it describes. PublishedAt is set once the relay has added it to
+// the event stream.
+type OutboxEvent struct {
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	AggregateType string     `gorm:"size:50;not null" json:"aggregate_type"`
+	AggregateID   uint       `gorm:"not null" json:"aggregate_id"`
+	EventType     string     `gorm:"size:100;not null" json:"event_type"`
+	Payload       string     `gorm:"type:text;not null" json:"payload"`
+	CreatedAt     time.Time  `json:"created_at"`
+	PublishedAt   *time.Time `gorm:"index" json:"published_at"`
+}
+
+// TableName keeps the conventional singular name of the outbox table.
+func (OutboxEvent) TableName() string { return "outbox" }
@@ -0,0 +1,19 @@
+package models
+
+// BookTranslation holds a book's title and description in a language other
+// than the catalog's default one, which the book's own fields are in.
+// Language is a canonical BCP 47 tag such as "tr" or "pt-BR".
+type BookTranslation struct {
+	BookID      uint   `gorm:"primaryKey" json:"book_id"`
+	Language    string `gorm:"primaryKey;size:35" json:"language"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+// AuthorTranslation holds an author's biography in a language other than
+// the catalog's default one.
+type AuthorTranslation struct {
+	AuthorID  uint   `gorm:"primaryKey" json:"author_id"`
+	Language  string `gorm:"primaryKey;size:35" json:"language"`
+	Biography string `json:"biography"`
+}
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// StringList is a list of strings stored as comma-separated text.
+type StringList []string
+
+func (l StringList) Value() (driver.Value, error) {
+	return strings.Join(l, ","), nil
+}
+
+func (l *StringList) Scan(src interface{}) error {
+	var s string
+	switch v := src.(type) {
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	case nil:
+	default:
+		return fmt.Errorf("cannot scan %T into StringList", src)
+	}
+	*l = nil
+	if s != "" {
+		*l = strings.Split(s, ",")
+	}
+	return nil
+}
+
+// Delivery statuses.
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryFailed    = "failed"
+)
+
+// WebhookSubscription registers a URL to receive the listed events. Deliveries
+// are signed with Secret.
+type WebhookSubscription struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	URL       string     `gorm:"not null" json:"url"`
+	Secret    string     `gorm:"not null" json:"-"`
+	Events    StringList `gorm:"type:text;not null" json:"events"`
+	Active    bool       `gorm:"not null;default:true" json:"active"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// WebhookDelivery is one event sent, or to be sent, to a subscription, and
+// the outcome of its latest attempt. A redelivery is a new row with the same
+// EventID and payload.
+type WebhookDelivery struct {
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	SubscriptionID uint       `gorm:"index;not null" json:"subscription_id"`
+	EventID        string     `gorm:"index;not null" json:"event_id"`
+	Event          string     `gorm:"not null" json:"event"`
+	Payload        string     `gorm:"type:text;not null" json:"payload"`
+	Status         string     `gorm:"index;not null" json:"status"`
+	Attempts       int        `gorm:"not null" json:"attempts"`
+	ResponseStatus int        `json:"response_status"`
+	LastError      string     `json:"last_error"`
+	NextAttemptAt  *time.Time `gorm:"index" json:"next_attempt_at"`
+	DeliveredAt    *time.Time `json:"delivered_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,72 @@
+package moderation
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/db"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/models"
+)
+
+// WordList returns a filter deciding decision for comments containing any
+// of words as a whole word, regardless of case. reason is formatted with
+// the word found.
+func WordList(decision Decision, reason string, words []string) Filter {
+	set := make(map[string]bool, len(words))
+	for _, w := range words {
+		if w = strings.ToLower(strings.TrimSpace(w)); w != "" {
+			set[w] = true
+		}
+	}
+	return FilterFunc(func(ctx context.Context, review models.Review) (Verdict, error) {
+		if len(set) == 0 {
+			return Verdict{}, nil
+		}
+		for _, word := range splitWords(review.Comment) {
+			if set[word] {
+				return Verdict{Decision: decision, Reasons: []string{fmt.Sprintf(reason, word)}}, nil
+			}
+		}
+		return Verdict{}, nil
+	})
+}
+
+// splitWords returns the lower-cased words of s, split at anything but
+// letters and digits.
+func splitWords(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+var linkPattern = regexp.MustCompile(`(?i)\b(?:https?://|www\.)\S+`)
+
+// LinkSpam returns a filter holding comments with more than max links.
+func LinkSpam(max int) Filter {
+	return FilterFunc(func(ctx context.Context, review models.Review) (Verdict, error) {
+		if n := len(linkPattern.FindAllStringIndex(review.Comment, -1)); n > max {
+			return Verdict{Decision: Hold, Reasons: []string{fmt.Sprintf("contains %d links", n)}}, nil
+		}
+		return Verdict{}, nil
+	})
+}
+
+// Duplicates returns a filter holding comments that repeat one posted, on
+// any book, within window.
+func Duplicates(window time.Duration) Filter {
+	return FilterFunc(func(ctx context.Context, review models.Review) (Verdict, error) {
+		var ids []uint
+		err := db.Reader(ctx).Model(&models.Review{}).
+			Where("comment_hash = ? AND created_at > ? AND id <> ?",
+				Fingerprint(review.Comment), time.Now().Add(-window), review.ID).
+			Order("id").Limit(1).Pluck("id", &ids).Error
+		if err != nil || len(ids) == 0 {
+			return Verdict{}, err
+		}
+		return Verdict{Decision: Hold, Reasons: []string{fmt.Sprintf("repeats review %d", ids[0])}}, nil
+	})
+}
@@ -0,0 +1,68 @@
+package moderation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/models"
+)
+
+func TestWordList(t *testing.T) {
+	filter := WordList(Reject, "contains the blocked word %q", []string{" Spam ", "scam", ""})
+
+	tests := []struct {
+		comment    string
+		want       Decision
+		wantReason string
+	}{
+		{"A fine book", Approve, ""},
+		{"This is spam", Reject, `contains the blocked word "spam"`},
+		{"SPAM, SPAM and eggs", Reject, `contains the blocked word "spam"`},
+		{"what a Scam!", Reject, `contains the blocked word "scam"`},
+		{"spammy but not spam-free", Reject, `contains the blocked word "spam"`},
+		{"spammy and scammer", Approve, ""},
+		{"antispam", Approve, ""},
+		{"", Approve, ""},
+	}
+	for _, tt := range tests {
+		v, err := filter.Check(context.Background(), models.Review{Comment: tt.comment})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v.Decision != tt.want || v.Reason() != tt.wantReason {
+			t.Errorf("Check(%q) = %v %q, want %v %q", tt.comment, v.Decision, v.Reason(), tt.want, tt.wantReason)
+		}
+	}
+}
+
+func TestWordListWithoutWords(t *testing.T) {
+	v, err := WordList(Hold, "contains %q", nil).Check(context.Background(), models.Review{Comment: "anything"})
+	if err != nil || v.Decision != Approve {
+		t.Errorf("Check = %v, %v, want Approve", v.Decision, err)
+	}
+}
+
+func TestLinkSpam(t *testing.T) {
+	filter := LinkSpam(2)
+
+	tests := []struct {
+		comment    string
+		want       Decision
+		wantReason string
+	}{
+		{"no links here", Approve, ""},
+		{"see https://example.com", Approve, ""},
+		{"http://a.example and www.b.example", Approve, ""},
+		{"http://a.example www.b.example HTTPS://c.example", Hold, "contains 3 links"},
+		{"example.com is not a link", Approve, ""},
+	}
+	for _, tt := range tests {
+		v, err := filter.Check(context.Background(), models.Review{Comment: tt.comment})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v.Decision != tt.want || v.Reason() != tt.wantReason {
+			t.Errorf("Check(%q) = %v %q, want %v %q", tt.comment, v.Decision, v.Reason(), tt.want, tt.wantReason)
+		}
+	}
+}
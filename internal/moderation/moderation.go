@@ -0,0 +1,116 @@
+// Package moderation screens reviews before they are shown. Each review
+// passes through a pipeline of filters, built from the configuration and
+// extensible with Use, whose verdicts decide whether it is approved, held
+// for a moderator or rejected.
+package moderation
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/config"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/models"
+)
+
+// Decision is a filter's ruling on a review, from the most to the least
+// lenient.
+type Decision int
+
+const (
+	Approve Decision = iota
+	Hold
+	Reject
+)
+
+// Verdict is the outcome of screening a review: the strictest decision of
+// the filters, with the reasons of those that did not approve it.
+type Verdict struct {
+	Decision Decision
+	Reasons  []string
+}
+
+// Reason joins the verdict's reasons into one sentence.
+func (v Verdict) Reason() string {
+	return strings.Join(v.Reasons, "; ")
+}
+
+// Filter inspects a review before it is stored. Review.ID is zero for new
+// reviews. Filters run in the transaction storing the review.
+type Filter interface {
+	Check(ctx context.Context, review models.Review) (Verdict, error)
+}
+
+// FilterFunc adapts a function to the Filter interface.
+type FilterFunc func(ctx context.Context, review models.Review) (Verdict, error)
+
+func (f FilterFunc) Check(ctx context.Context, review models.Review) (Verdict, error) {
+	return f(ctx, review)
+}
+
+var (
+	filters         []Filter
+	requireApproval bool
+	moderatorTokens []string
+)
+
+// Init builds the filter pipeline and moderator list from cfg.
+func Init(cfg config.ModerationConfig) {
+	requireApproval = cfg.RequireApproval
+	moderatorTokens = cfg.ModeratorTokens
+	filters = []Filter{
+		WordList(Reject, "contains the blocked word %q", cfg.BlockedWords),
+		WordList(Hold, "contains the flagged word %q", cfg.FlaggedWords),
+		LinkSpam(cfg.MaxLinks),
+	}
+	if cfg.DuplicateWindow > 0 {
+		filters = append(filters, Duplicates(cfg.DuplicateWindow))
+	}
+}
+
+// Use adds filters to the end of the pipeline. It is meant to be called at
+// startup, after Init.
+func Use(f ...Filter) {
+	filters = append(filters, f...)
+}
+
+// Screen runs the review through every filter and combines their verdicts.
+func Screen(ctx context.Context, review models.Review) (Verdict, error) {
+	var verdict Verdict
+	for _, f := range filters {
+		v, err := f.Check(ctx, review)
+		if err != nil {
+			return Verdict{}, err
+		}
+		if v.Decision > verdict.Decision {
+			verdict.Decision = v.Decision
+		}
+		if v.Decision != Approve {
+			verdict.Reasons = append(verdict.Reasons, v.Reasons...)
+		}
+	}
+	return verdict, nil
+}
+
+// Status returns the status a new review with this verdict is stored with.
+// Reviews the filters approve wait for a moderator too when every review
+// requires approval.
+func (v Verdict) Status() string {
+	switch {
+	case v.Decision == Reject:
+		return models.ReviewRejected
+	case v.Decision == Hold || requireApproval:
+		return models.ReviewPending
+	default:
+		return models.ReviewApproved
+	}
+}
+
+// Fingerprint returns the hash duplicate reviews share: that of the comment
+// in lower case with its whitespace collapsed.
+func Fingerprint(comment string) string {
+	normalized := strings.Join(strings.Fields(strings.ToLower(comment)), " ")
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,111 @@
+package moderation
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/models"
+)
+
+// ruling returns a filter deciding decision for every review.
+func ruling(decision Decision, reasons ...string) Filter {
+	return FilterFunc(func(ctx context.Context, review models.Review) (Verdict, error) {
+		return Verdict{Decision: decision, Reasons: reasons}, nil
+	})
+}
+
+// setFilters replaces the pipeline for the duration of the test.
+func setFilters(t *testing.T, f ...Filter) {
+	t.Helper()
+	saved := filters
+	filters = f
+	t.Cleanup(func() { filters = saved })
+}
+
+func TestScreen(t *testing.T) {
+	tests := []struct {
+		name       string
+		filters    []Filter
+		want       Decision
+		wantReason string
+	}{
+		{"no filters", nil, Approve, ""},
+		{"all approve", []Filter{ruling(Approve), ruling(Approve, "ignored")}, Approve, ""},
+		{"one holds", []Filter{ruling(Approve), ruling(Hold, "flagged")}, Hold, "flagged"},
+		{"reject wins over hold", []Filter{ruling(Hold, "flagged"), ruling(Reject, "blocked")}, Reject, "flagged; blocked"},
+		{"hold after reject", []Filter{ruling(Reject, "blocked"), ruling(Hold, "links")}, Reject, "blocked; links"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setFilters(t, tt.filters...)
+			v, err := Screen(context.Background(), models.Review{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if v.Decision != tt.want || v.Reason() != tt.wantReason {
+				t.Errorf("Screen = %v %q, want %v %q", v.Decision, v.Reason(), tt.want, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestScreenStopsAtError(t *testing.T) {
+	failure := errors.New("lookup failed")
+	calls := 0
+	setFilters(t,
+		FilterFunc(func(ctx context.Context, review models.Review) (Verdict, error) {
+			return Verdict{}, failure
+		}),
+		FilterFunc(func(ctx context.Context, review models.Review) (Verdict, error) {
+			calls++
+			return Verdict{}, nil
+		}),
+	)
+	if _, err := Screen(context.Background(), models.Review{}); !errors.Is(err, failure) {
+		t.Errorf("Screen error = %v, want %v", err, failure)
+	}
+	if calls != 0 {
+		t.Errorf("filters after the failing one ran %d times", calls)
+	}
+}
+
+func TestVerdictStatus(t *testing.T) {
+	tests := []struct {
+		decision        Decision
+		requireApproval bool
+		want            string
+	}{
+		{Approve, false, models.ReviewApproved},
+		{Approve, true, models.ReviewPending},
+		{Hold, false, models.ReviewPending},
+		{Hold, true, models.ReviewPending},
+		{Reject, false, models.ReviewRejected},
+		{Reject, true, models.ReviewRejected},
+	}
+	saved := requireApproval
+	t.Cleanup(func() { requireApproval = saved })
+	for _, tt := range tests {
+		requireApproval = tt.requireApproval
+		if got := (Verdict{Decision: tt.decision}).Status(); got != tt.want {
+			t.Errorf("Status of %v with requireApproval %v = %q, want %q", tt.decision, tt.requireApproval, got, tt.want)
+		}
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	base := Fingerprint("great book")
+	if len(base) != 64 {
+		t.Errorf("Fingerprint length = %d, want 64", len(base))
+	}
+	for _, same := range []string{"Great Book", "  great   book ", "great\n\tbook"} {
+		if got := Fingerprint(same); got != base {
+			t.Errorf("Fingerprint(%q) differs from Fingerprint(%q)", same, "great book")
+		}
+	}
+	for _, other := range []string{"great books", "greatbook", "great, book"} {
+		if got := Fingerprint(other); got == base {
+			t.Errorf("Fingerprint(%q) matches Fingerprint(%q)", other, "great book")
+		}
+	}
+}
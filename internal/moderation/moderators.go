@@ -0,0 +1,18 @@
+package moderation
+
+import (
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/auth"
+	"github.com/gin-gonic/gin"
+)
+
+// IsModerator reports whether the request carries one of the configured
+// moderator tokens as its bearer token.
+func IsModerator(c *gin.Context) bool {
+	return auth.HasToken(c, moderatorTokens)
+}
+
+// RequireModerator answers 401 to requests without a bearer token and 403
+// to those whose token is not a moderator's.
+func RequireModerator() gin.HandlerFunc {
+	return auth.Require(IsModerator, "A moderator token is required", "Only moderators may do this")
+}
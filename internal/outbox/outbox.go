@@ -0,0 +1,71 @@
+// Package outbox implements the transactional outbox for catalog events.
+// Mutations record an event in the outbox table inside their transaction,
+// so an event exists exactly when its change was committed. A relay then
+// publishes the recorded events to Redis Streams in commit order, marking
+// them published only after Redis has accepted them: delivery is at least
+// once, and consumers deduplicate on the event ID. Each published event is
+// also broadcast on a Redis pub/sub channel for live listeners.
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/db"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/models"
+)
+
+// Aggregate types.
+const (
+	Author = "author"
+	Book   = "book"
+	Review = "review"
+)
+
+// Actions, appended to the aggregate type to form the event type, e.g.
+// "book.created".
+const (
+	Created = "created"
+	Updated = "updated"
+	Deleted = "deleted"
+)
+
+// Message is an event as broadcast on the pub/sub channel.
+type Message struct {
+	ID            uint            `json:"id"`
+	Type          string          `json:"type"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   uint            `json:"aggregate_id"`
+	CreatedAt     time.Time       `json:"created_at"`
+	Data          json.RawMessage `json:"data"`
+}
+
+// NewMessage converts a recorded event.
+func NewMessage(event models.OutboxEvent) Message {
+	return Message{
+		ID:            event.ID,
+		Type:          event.EventType,
+		AggregateType: event.AggregateType,
+		AggregateID:   event.AggregateID,
+		CreatedAt:     event.CreatedAt,
+		Data:          json.RawMessage(event.Payload),
+	}
+}
+
+// Record adds an event about the aggregate to the outbox. It must be called
+// with the context of the db.Transaction making the change, so the event is
+// committed or rolled back with it.
+func Record(ctx context.Context, aggregateType string, aggregateID uint, action string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	event := models.OutboxEvent{
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		EventType:     aggregateType + "." + action,
+		Payload:       string(payload),
+	}
+	return db.Writer(ctx).Create(&event).Error
+}
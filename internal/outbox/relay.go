@@ -0,0 +1,208 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/cache"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/config"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/db"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/models"
+	"github.com/go-redis/redis/v8"
+	"gorm.io/gorm"
+)
+
+// relayLockKey is the PostgreSQL advisory lock held while publishing, so
+// only one instance relays at a time and events leave in ID order.
+const relayLockKey = 0x6f7574626f78 // "outbox"
+
+// cleanupInterval is how often published events past retention are deleted.
+const cleanupInterval = time.Hour
+
+var (
+	stop    = make(chan struct{})
+	running sync.WaitGroup
+)
+
+type relay struct {
+	cfg         config.OutboxConfig
+	groupsReady bool
+	lastCleanup time.Time
+}
+
+// Start runs the relay until Stop is called, publishing new events every
+// cfg.PollInterval.
+func Start(cfg config.OutboxConfig) {
+	r := &relay{cfg: cfg}
+	running.Add(1)
+	go func() {
+		defer running.Done()
+		ticker := time.NewTicker(cfg.PollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+			r.run(context.Background())
+		}
+	}()
+}
+
+// Stop stops the relay after the batch in progress.
+func Stop() {
+	close(stop)
+	running.Wait()
+}
+
+// Stream returns the stream carrying the aggregate's events. All events of
+// one aggregate go to the same stream, so they keep their order however
+// many partitions there are.
+func Stream(cfg config.OutboxConfig, aggregateType string, aggregateID uint) string {
+	if cfg.Partitions <= 1 {
+		return cfg.Stream
+	}
+	h := fnv.New32a()
+	fmt.Fprintf(h, "%s:%d", aggregateType, aggregateID)
+	return partition(cfg, int(h.Sum32()%uint32(cfg.Partitions)))
+}
+
+func partition(cfg config.OutboxConfig, n int) string {
+	if cfg.Partitions <= 1 {
+		return cfg.Stream
+	}
+	return cfg.Stream + "." + strconv.Itoa(n)
+}
+
+// run publishes every pending event, in batches of cfg.BatchSize.
+func (r *relay) run(ctx context.Context) {
+	if !r.groupsReady {
+		if err := r.createGroups(ctx); err != nil {
+			slog.Warn("Creating outbox consumer groups failed", "error", err)
+			return
+		}
+		r.groupsReady = true
+	}
+
+	for {
+		n, err := r.publishBatch(ctx)
+		if err != nil {
+			slog.Error("Publishing outbox events failed", "published", n, "error", err)
+			return
+		}
+		if n < r.cfg.BatchSize {
+			break
+		}
+		select {
+		case <-stop:
+			return
+		default:
+		}
+	}
+
+	if time.Since(r.lastCleanup) >= cleanupInterval {
+		r.lastCleanup = time.Now()
+		cutoff := time.Now().Add(-r.cfg.Retention)
+		if err := db.DB.WithContext(ctx).Where("published_at < ?", cutoff).Delete(&models.OutboxEvent{}).Error; err != nil {
+			slog.Error("Deleting published outbox events failed", "error", err)
+		}
+	}
+}
+
+// createGroups creates the configured consumer groups on every partition,
+// creating the streams if needed. Groups start from the beginning of the
+// stream so consumers see every event the stream still holds.
+func (r *relay) createGroups(ctx context.Context) error {
+	for _, group := range r.cfg.Groups {
+		for n := 0; n < r.cfg.Partitions; n++ {
+			err := cache.RDB.XGroupCreateMkStream(ctx, partition(r.cfg, n), group, "0").Err()
+			if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// publishBatch adds the oldest unpublished events to their streams one by
+// one and marks those Redis accepted as published. It stops at the first
+// failure so no event overtakes an earlier one; an event added but not
+// marked, because the update failed, is published again.
+func (r *relay) publishBatch(ctx context.Context) (int, error) {
+	var published int
+	var publishErr error
+	err := db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var locked bool
+		if err := tx.Raw("SELECT pg_try_advisory_xact_lock(?)", relayLockKey).Scan(&locked).Error; err != nil {
+			return err
+		}
+		if !locked {
+			// Another instance is relaying
+			return nil
+		}
+
+		var events []models.OutboxEvent
+		err := tx.Where("published_at IS NULL").Order("id").Limit(r.cfg.BatchSize).Find(&events).Error
+		if err != nil {
+			return err
+		}
+		ids := make([]uint, 0, len(events))
+		for _, event := range events {
+			if publishErr = r.add(ctx, event); publishErr != nil {
+				break
+			}
+			ids = append(ids, event.ID)
+			r.broadcast(ctx, event)
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		published = len(ids)
+		return tx.Model(&models.OutboxEvent{}).Where("id IN ?", ids).Update("published_at", time.Now()).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return published, publishErr
+}
+
+// add appends one event to its stream.
+func (r *relay) add(ctx context.Context, event models.OutboxEvent) error {
+	return cache.RDB.XAdd(ctx, &redis.XAddArgs{
+		Stream: Stream(r.cfg, event.AggregateType, event.AggregateID),
+		MaxLen: int64(r.cfg.MaxLen),
+		Approx: true,
+		Values: map[string]interface{}{
+			"id":             event.ID,
+			"type":           event.EventType,
+			"aggregate_type": event.AggregateType,
+			"aggregate_id":   event.AggregateID,
+			"created_at":     event.CreatedAt.UTC().Format(time.RFC3339Nano),
+			"payload":        event.Payload,
+		},
+	}).Err()
+}
+
+// broadcast announces a published event to live listeners. Pub/sub keeps no
+// history, so a failure is only logged; listeners catch up from the outbox
+// table when they reconnect.
+func (r *relay) broadcast(ctx context.Context, event models.OutboxEvent) {
+	if r.cfg.Channel == "" {
+		return
+	}
+	msg, err := json.Marshal(NewMessage(event))
+	if err == nil {
+		err = cache.RDB.Publish(ctx, r.cfg.Channel, msg).Err()
+	}
+	if err != nil {
+		slog.Warn("Broadcasting outbox event failed", "id", event.ID, "error", err)
+	}
+}
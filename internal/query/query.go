@@ -0,0 +1,207 @@
+// Package query translates ?include= and ?fields[...]= parameters into GORM
+// preloads and column selections, and trims responses to the requested shape.
+package query
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Options holds the parsed include and fields parameters for one request.
+type Options struct {
+	resource string
+	// includes holds every include path, with intermediate levels expanded,
+	// e.g. "author.books" also yields "author".
+	includes []string
+	// fields maps a resource name to the JSON fields requested for it.
+	fields map[string][]string
+}
+
+// Parse reads ?include= and ?fields[...]= for the given top-level resource.
+// defaultInclude is used when the request has no include parameter at all.
+func Parse(c *gin.Context, resource string, defaultInclude ...string) (*Options, error) {
+	if _, ok := resources[resource]; !ok {
+		return nil, fmt.Errorf("unknown resource %q", resource)
+	}
+	opts := &Options{resource: resource, fields: map[string][]string{}}
+
+	include := defaultInclude
+	if raw, ok := c.GetQuery("include"); ok {
+		include = splitList(raw)
+	}
+	seen := map[string]bool{}
+	for _, path := range include {
+		if err := opts.addInclude(path, seen); err != nil {
+			return nil, err
+		}
+	}
+
+	for name, raw := range c.QueryMap("fields") {
+		res, ok := resources[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown resource %q in fields", name)
+		}
+		for _, field := range splitList(raw) {
+			if _, ok := res.Columns[field]; !ok && field != "id" {
+				return nil, fmt.Errorf("unknown field %q for %s", field, name)
+			}
+			opts.fields[name] = append(opts.fields[name], field)
+		}
+	}
+	return opts, nil
+}
+
+func (o *Options) addInclude(path string, seen map[string]bool) error {
+	parts := strings.Split(path, ".")
+	if len(parts) > MaxIncludeDepth {
+		return fmt.Errorf("include %q exceeds the maximum depth of %d", path, MaxIncludeDepth)
+	}
+	res := resources[o.resource]
+	for i, part := range parts {
+		rel, ok := res.Relations[part]
+		if !ok {
+			return fmt.Errorf("cannot include %q", strings.Join(parts[:i+1], "."))
+		}
+		prefix := strings.Join(parts[:i+1], ".")
+		if !seen[prefix] {
+			seen[prefix] = true
+			o.includes = append(o.includes, prefix)
+		}
+		res = resources[rel.Resource]
+	}
+	return nil
+}
+
+// Apply adds the column selection and preloads to tx.
+func (o *Options) Apply(tx *gorm.DB) *gorm.DB {
+	if cols := o.columns(o.resource); cols != nil {
+		tx = tx.Select(cols)
+	}
+	for _, path := range o.includes {
+		association, rel := o.resolve(path)
+		cols := o.columns(rel.Resource)
+		if cols == nil && rel.Order == "" && rel.Where == "" {
+			tx = tx.Preload(association)
+			continue
+		}
+		tx = tx.Preload(association, func(db *gorm.DB) *gorm.DB {
+			if cols != nil {
+				db = db.Select(cols)
+			}
+			if rel.Where != "" {
+				db = db.Where(rel.Where)
+			}
+			if rel.Order != "" {
+				db = db.Order(rel.Order)
+			}
+			return db
+		})
+	}
+	return tx
+}
+
+// resolve maps an include path to its GORM association path and the last
+// relation on it.
+func (o *Options) resolve(path string) (string, Relation) {
+	res := o.resource
+	var rel Relation
+	var associations []string
+	for _, part := range strings.Split(path, ".") {
+		rel = resources[res].Relations[part]
+		associations = append(associations, rel.Association)
+		res = rel.Resource
+	}
+	return strings.Join(associations, "."), rel
+}
+
+// columns returns the columns to select for resource, or nil to select all.
+func (o *Options) columns(resource string) []string {
+	fields, ok := o.fields[resource]
+	if !ok {
+		return nil
+	}
+	res := resources[resource]
+	set := map[string]bool{}
+	for _, col := range res.Required {
+		set[col] = true
+	}
+	for _, field := range fields {
+		if col, ok := res.Columns[field]; ok {
+			set[col] = true
+		}
+	}
+	cols := make([]string, 0, len(set))
+	for col := range set {
+		cols = append(cols, col)
+	}
+	sort.Strings(cols)
+	return cols
+}
+
+// Shape trims v, a response view or slice of views for the top-level
+// resource, down to the requested fields. The id, included relations and
+// computed fields are always kept.
+func (o *Options) Shape(v interface{}) (interface{}, error) {
+	if len(o.fields) == 0 {
+		return v, nil
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var generic interface{}
+	if err := dec.Decode(&generic); err != nil {
+		return nil, err
+	}
+	return o.prune(o.resource, generic), nil
+}
+
+func (o *Options) prune(resource string, v interface{}) interface{} {
+	switch val := v.(type) {
+	case []interface{}:
+		for i := range val {
+			val[i] = o.prune(resource, val[i])
+		}
+		return val
+	case map[string]interface{}:
+		res := resources[resource]
+		fields, limited := o.fields[resource]
+		keep := map[string]bool{"id": true}
+		for _, f := range res.Computed {
+			keep[f] = true
+		}
+		for _, f := range fields {
+			keep[f] = true
+		}
+		for key, child := range val {
+			if rel, ok := res.Relations[key]; ok {
+				val[key] = o.prune(rel.Resource, child)
+				continue
+			}
+			if limited && !keep[key] {
+				delete(val, key)
+			}
+		}
+		return val
+	default:
+		return v
+	}
+}
+
+func splitList(raw string) []string {
+	var out []string
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
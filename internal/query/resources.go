@@ -0,0 +1,129 @@
+package query
+
+// Relation is an embeddable relationship of a resource.
+type Relation struct {
+	// Association is the GORM association name passed to Preload.
+	Association string
+	// Resource is the resource type of the related rows.
+	Resource string
+	// Order, if set, sorts the related rows.
+	Order string
+	// Where, if set, restricts the related rows.
+	Where string
+}
+
+// Resource describes which fields and relations of a resource clients may
+// request through ?fields[...]= and ?include=.
+type Resource struct {
+	// Columns maps JSON field names to table columns.
+	Columns map[string]string
+	// Required columns are always selected so primary keys and the foreign
+	// keys used by Preload are available.
+	Required []string
+	// Relations maps include names to relationships.
+	Relations map[string]Relation
+	// Computed fields are opt-in through their own parameters and are kept
+	// regardless of the requested fieldset.
+	Computed []string
+}
+
+// MaxIncludeDepth limits how deeply relations can be nested, e.g. "author.books".
+const MaxIncludeDepth = 2
+
+var resources = map[string]Resource{
+	"books": {
+		Columns: map[string]string{
+			"title":            "title",
+			"author_id":        "author_id",
+			"isbn":             "isbn",
+			"publication_year": "publication_year",
+			"description":      "description",
+			"cover_url":        "cover_key",
+			"cover_thumbnails": "cover_key",
+			"series_id":        "series_id",
+			"series_position":  "series_position",
+		},
+		Required: []string{"id", "author_id", "series_id"},
+		Relations: map[string]Relation{
+			"author":  {Association: "Author", Resource: "authors"},
+			"reviews": {Association: "Reviews", Resource: "reviews", Where: "status = 'approved'"},
+			"genres":  {Association: "Genres", Resource: "genres"},
+			"contributors": {
+				Association: "Contributors", Resource: "contributors", Order: "position, role",
+			},
+			"editions": {Association: "Editions", Resource: "editions", Order: "id"},
+			"series":   {Association: "Series", Resource: "series"},
+		},
+		Computed: []string{"language"},
+	},
+	"authors": {
+		Columns: map[string]string{
+			"name":       "name",
+			"biography":  "biography",
+			"birth_date": "birth_date",
+		},
+		Required: []string{"id"},
+		Relations: map[string]Relation{
+			"books": {Association: "Books", Resource: "books"},
+		},
+		Computed: []string{"stats", "language"},
+	},
+	"reviews": {
+		Columns: map[string]string{
+			"book_id":     "book_id",
+			"rating":      "rating",
+			"comment":     "comment",
+			"date_posted": "date_posted",
+			"status":      "status",
+		},
+		Required: []string{"id", "book_id"},
+	},
+	"contributors": {
+		Columns: map[string]string{
+			"author_id": "author_id",
+			"role":      "role",
+			"position":  "position",
+		},
+		Required: []string{"book_id", "author_id", "role"},
+		Relations: map[string]Relation{
+			"author": {Association: "Author", Resource: "authors"},
+		},
+	},
+	"editions": {
+		Columns: map[string]string{
+			"book_id":      "book_id",
+			"isbn":         "isbn",
+			"format":       "format",
+			"page_count":   "page_count",
+			"language":     "language",
+			"publisher_id": "publisher_id",
+		},
+		Required: []string{"id", "book_id", "publisher_id"},
+		Relations: map[string]Relation{
+			"publisher": {Association: "Publisher", Resource: "publishers"},
+		},
+	},
+	"publishers": {
+		Columns: map[string]string{
+			"name":    "name",
+			"website": "website",
+		},
+		Required: []string{"id"},
+	},
+	"series": {
+		Columns: map[string]string{
+			"name":        "name",
+			"description": "description",
+		},
+		Required: []string{"id"},
+	},
+	"genres": {
+		Columns: map[string]string{
+			"name":        "name",
+			"slug":        "slug",
+			"description": "description",
+			"parent_id":   "parent_id",
+		},
+		Required: []string{"id"},
+	},
+}
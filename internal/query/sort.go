@@ -0,0 +1,46 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ParseSort reads ?sort= for resource, e.g. "-publication_year,title", and
+// returns an ORDER BY clause. A leading "-" sorts descending. Only whitelisted
+// fields are accepted; fallback is used when the parameter is absent. Rows
+// tied on the requested fields are ordered by id, so pages neither repeat
+// nor skip rows.
+func ParseSort(c *gin.Context, resource, fallback string) (string, error) {
+	res, ok := resources[resource]
+	if !ok {
+		return "", fmt.Errorf("unknown resource %q", resource)
+	}
+	raw := c.Query("sort")
+	if raw == "" {
+		return fallback, nil
+	}
+
+	var clauses []string
+	byID := false
+	for _, field := range splitList(raw) {
+		dir := "ASC"
+		if strings.HasPrefix(field, "-") {
+			dir = "DESC"
+			field = field[1:]
+		}
+		col, ok := res.Columns[field]
+		if field == "id" {
+			col, ok, byID = "id", true, true
+		}
+		if !ok {
+			return "", fmt.Errorf("cannot sort %s by %q", resource, field)
+		}
+		clauses = append(clauses, col+" "+dir)
+	}
+	if !byID {
+		clauses = append(clauses, "id ASC")
+	}
+	return strings.Join(clauses, ", "), nil
+}
@@ -1,32 +1,125 @@
 package routes
 
 import (
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/config"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/gql"
 	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/handlers"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/idempotency"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/moderation"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/webhooks"
 	"github.com/gin-gonic/gin"
 )
 
-func SetupRoutes(r *gin.Engine) {
+// LongRunningRoutes are the endpoints the request timeout does not apply
+// to: Server-Sent Events streams, and attachment transfers, which set their
+// own.
+var LongRunningRoutes = []string{
+	"/api/v1/events",
+	"/api/v1/books/:id/reviews/stream",
+	"/api/v1/authors/:id/attachments",
+	"/api/v1/books/:id/attachments",
+	"/api/v1/attachments/:id/download",
+}
+
+func SetupRoutes(r *gin.Engine, cfg config.Config) {
+	// Creating endpoints that clients retry accept an Idempotency-Key
+	idempotent := idempotency.Middleware(cfg.Idempotency)
+
 	api := r.Group("/api/v1")
 	{
 		// Book endpoints
 		api.GET("/books", handlers.GetBooks)
 		api.GET("/books/:id", handlers.GetBookByID)
-		api.POST("/books", handlers.CreateBook)
+		api.POST("/books", idempotent, handlers.CreateBook)
 		api.PUT("/books/:id", handlers.UpdateBook)
 		api.DELETE("/books/:id", handlers.DeleteBook)
+		api.PUT("/books/:id/cover", handlers.UploadBookCover(cfg.Covers))
+		api.DELETE("/books/:id/cover", handlers.DeleteBookCover)
+		api.GET("/books/:id/attachments", handlers.GetBookAttachments)
+		api.POST("/books/:id/attachments", handlers.UploadBookAttachment(cfg.Attachments))
 
 		// Author endpoints
 		api.GET("/authors", handlers.GetAuthors)
 		api.GET("/authors/:id", handlers.GetAuthorByID)
+		api.GET("/authors/:id/books", handlers.GetAuthorBooks)
 		api.POST("/authors", handlers.CreateAuthor)
 		api.PUT("/authors/:id", handlers.UpdateAuthor)
 		api.DELETE("/authors/:id", handlers.DeleteAuthor)
+		api.GET("/authors/:id/attachments", handlers.GetAuthorAttachments)
+		api.POST("/authors/:id/attachments", handlers.UploadAuthorAttachment(cfg.Attachments))
+
+		// Genre hierarchy
+		api.GET("/genres", handlers.GetGenres)
+		api.GET("/genres/:id", handlers.GetGenreByID)
+		api.POST("/genres", handlers.CreateGenre)
+		api.PUT("/genres/:id", handlers.UpdateGenre)
+		api.DELETE("/genres/:id", handlers.DeleteGenre)
+
+		// Editions of books and their publishers
+		api.GET("/books/:id/editions", handlers.GetBookEditions)
+		api.POST("/books/:id/editions", handlers.CreateEdition)
+		api.GET("/editions/:id", handlers.GetEditionByID)
+		api.PUT("/editions/:id", handlers.UpdateEdition)
+		api.DELETE("/editions/:id", handlers.DeleteEdition)
+		api.GET("/publishers", handlers.GetPublishers)
+		api.GET("/publishers/:id", handlers.GetPublisherByID)
+		api.POST("/publishers", handlers.CreatePublisher)
+		api.PUT("/publishers/:id", handlers.UpdatePublisher)
+		api.DELETE("/publishers/:id", handlers.DeletePublisher)
+
+		// Series of books
+		api.GET("/series", handlers.GetSeries)
+		api.GET("/series/:id", handlers.GetSeriesByID)
+		api.POST("/series", handlers.CreateSeries)
+		api.PUT("/series/:id", handlers.UpdateSeries)
+		api.DELETE("/series/:id", handlers.DeleteSeries)
+
+		// Book and author fields in other languages
+		api.GET("/books/:id/translations", handlers.GetBookTranslations)
+		api.PUT("/books/:id/translations/:language", handlers.PutBookTranslation)
+		api.DELETE("/books/:id/translations/:language", handlers.DeleteBookTranslation)
+		api.GET("/authors/:id/translations", handlers.GetAuthorTranslations)
+		api.PUT("/authors/:id/translations/:language", handlers.PutAuthorTranslation)
+		api.DELETE("/authors/:id/translations/:language", handlers.DeleteAuthorTranslation)
+
+		// Attachments of authors and books
+		api.GET("/attachments/:id", handlers.GetAttachment)
+		api.GET("/attachments/:id/download", handlers.DownloadAttachment(cfg.Attachments))
+		api.DELETE("/attachments/:id", handlers.DeleteAttachment)
 
 		// Review endpoints
 		api.GET("/books/:id/reviews", handlers.GetReviewsForBook)
-		api.POST("/books/:id/reviews", handlers.CreateReview)
+		api.POST("/books/:id/reviews", idempotent, handlers.CreateReview)
 		api.PUT("/reviews/:id", handlers.UpdateReview)
 		api.DELETE("/reviews/:id", handlers.DeleteReview)
+
+		// Moderator queue of held reviews
+		moderated := api.Group("/moderation", moderation.RequireModerator())
+		moderated.GET("/reviews", handlers.GetModerationQueue)
+		moderated.PUT("/reviews/:id", handlers.ModerateReview)
+
+		// Live review activity and catalog changes
+		api.GET("/books/:id/reviews/stream", handlers.StreamBookReviews(cfg.Events))
+		api.GET("/events", handlers.StreamEvents(cfg.Events))
+
+		// Several operations in one transaction
+		api.POST("/batch", handlers.ExecuteBatch)
+
+		// Webhook subscriptions and their delivery log, for admins only
+		admin := api.Group("/webhooks", webhooks.RequireAdmin(cfg.Webhooks))
+		admin.GET("", handlers.GetWebhooks)
+		admin.GET("/:id", handlers.GetWebhookByID)
+		admin.POST("", handlers.CreateWebhook)
+		admin.PUT("/:id", handlers.UpdateWebhook)
+		admin.DELETE("/:id", handlers.DeleteWebhook)
+		admin.GET("/:id/deliveries", handlers.GetWebhookDeliveries)
+		admin.POST("/:id/deliveries/:delivery_id/redeliver", handlers.RedeliverWebhook)
 	}
 
+	// Stored files such as cover images
+	r.GET("/media/*key", handlers.ServeMedia)
+
+	// GraphQL endpoint
+	r.POST("/graphql", gql.Handler())
+
 }
@@ -0,0 +1,257 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"strconv"
+
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/attachments"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/blob"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/config"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/db"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrAttachmentNotFound = errors.New("attachment not found")
+	ErrQuotaExceeded      = errors.New("storage quota exceeded")
+)
+
+// AttachmentUpload is a file to attach to an author or a book.
+type AttachmentUpload struct {
+	OwnerType string
+	OwnerID   uint
+	Name      string
+	File      io.ReadSeeker
+}
+
+// ListAttachments returns the attachments of an existing author or book,
+// oldest first.
+func ListAttachments(ctx context.Context, ownerType string, ownerID uint) ([]models.Attachment, error) {
+	if err := ownerExists(ctx, ownerType, ownerID, false); err != nil {
+		return nil, err
+	}
+	var atts []models.Attachment
+	err := db.Reader(ctx).Where("owner_type = ? AND owner_id = ?", ownerType, ownerID).
+		Order("id").Find(&atts).Error
+	return atts, err
+}
+
+// GetAttachment returns the attachment with the given ID.
+func GetAttachment(ctx context.Context, id uint) (models.Attachment, error) {
+	var att models.Attachment
+	if err := db.Reader(ctx).First(&att, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return att, ErrAttachmentNotFound
+		}
+		return att, err
+	}
+	return att, nil
+}
+
+// CreateAttachment attaches the uploaded file to its owner and reports
+// whether a new attachment was created: uploading a file the owner already
+// has returns the existing attachment. The file is stored under its
+// checksum, so identical files attached elsewhere are stored only once and
+// count once towards the total quota.
+func CreateAttachment(ctx context.Context, limits config.AttachmentsConfig, upload AttachmentUpload) (models.Attachment, bool, error) {
+	ctx = db.WithPrimary(ctx)
+	head := make([]byte, attachments.SniffLen)
+	n, err := io.ReadFull(upload.File, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return models.Attachment{}, false, err
+	}
+	contentType, err := attachments.Detect(upload.OwnerType, head[:n])
+	if err != nil {
+		return models.Attachment{}, false, err
+	}
+	hash := sha256.New()
+	if _, err := upload.File.Seek(0, io.SeekStart); err != nil {
+		return models.Attachment{}, false, err
+	}
+	size, err := io.Copy(hash, upload.File)
+	if err != nil {
+		return models.Attachment{}, false, err
+	}
+	checksum := hex.EncodeToString(hash.Sum(nil))
+
+	att := models.Attachment{
+		OwnerType:   upload.OwnerType,
+		OwnerID:     upload.OwnerID,
+		Name:        upload.Name,
+		ContentType: contentType,
+		Size:        size,
+		Checksum:    checksum,
+		StorageKey:  attachments.Key(checksum, contentType),
+	}
+	created := false
+	err = db.Transaction(ctx, func(ctx context.Context) error {
+		w := db.Writer(ctx)
+		// Keep the owner from being deleted, and other uploads for it from
+		// passing the quota check at the same time
+		if err := ownerExists(ctx, att.OwnerType, att.OwnerID, true); err != nil {
+			return err
+		}
+		if err := advisoryLock(ctx, "attachments:"+att.OwnerType+":"+uintString(att.OwnerID)); err != nil {
+			return err
+		}
+
+		var existing models.Attachment
+		err := w.Where("owner_type = ? AND owner_id = ? AND checksum = ?", att.OwnerType, att.OwnerID, checksum).
+			First(&existing).Error
+		if err == nil {
+			att = existing
+			return nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		var used int64
+		err = w.Model(&models.Attachment{}).Where("owner_type = ? AND owner_id = ?", att.OwnerType, att.OwnerID).
+			Select("COALESCE(SUM(size), 0)").Scan(&used).Error
+		if err != nil {
+			return err
+		}
+		if used+size > int64(limits.OwnerQuota) {
+			return ErrQuotaExceeded
+		}
+
+		// Serialize with uploads and removals of the same content
+		if err := advisoryLock(ctx, "blob:"+checksum); err != nil {
+			return err
+		}
+		var refs int64
+		if err := w.Model(&models.Attachment{}).Where("checksum = ?", checksum).Count(&refs).Error; err != nil {
+			return err
+		}
+		if refs == 0 {
+			if limits.TotalQuota > 0 {
+				var total int64
+				err := w.Raw("SELECT COALESCE(SUM(size), 0) FROM (SELECT DISTINCT ON (checksum) size FROM attachments) AS stored").
+					Scan(&total).Error
+				if err != nil {
+					return err
+				}
+				if total+size > int64(limits.TotalQuota) {
+					return ErrQuotaExceeded
+				}
+			}
+			if _, err := upload.File.Seek(0, io.SeekStart); err != nil {
+				return err
+			}
+			if err := blob.Default.Put(ctx, att.StorageKey, upload.File, size, contentType); err != nil {
+				return err
+			}
+		}
+
+		created = true
+		return w.Create(&att).Error
+	})
+	return att, created, err
+}
+
+// DeleteAttachment deletes the attachment with the given ID and returns it.
+// Its file is removed once no attachment refers to it.
+func DeleteAttachment(ctx context.Context, id uint) (models.Attachment, error) {
+	ctx = db.WithPrimary(ctx)
+	var att models.Attachment
+	err := db.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		if att, err = GetAttachment(ctx, id); err != nil {
+			return err
+		}
+		if err := db.Writer(ctx).Delete(&att).Error; err != nil {
+			return err
+		}
+		releaseAttachmentFile(ctx, att)
+		return nil
+	})
+	return att, err
+}
+
+// deleteAttachments deletes the attachments of the given owners, for
+// owners being deleted; polymorphic associations have no foreign key to
+// cascade.
+func deleteAttachments(ctx context.Context, ownerType string, ownerIDs []uint) error {
+	if len(ownerIDs) == 0 {
+		return nil
+	}
+	var atts []models.Attachment
+	err := db.Writer(ctx).Clauses(clause.Returning{}).
+		Where("owner_type = ? AND owner_id IN ?", ownerType, ownerIDs).Delete(&atts).Error
+	if err != nil {
+		return err
+	}
+	released := make(map[string]bool)
+	for _, att := range atts {
+		if !released[att.Checksum] {
+			released[att.Checksum] = true
+			releaseAttachmentFile(ctx, att)
+		}
+	}
+	return nil
+}
+
+// releaseAttachmentFile removes the attachment's file after the deletion
+// commits, unless another attachment still refers to it by then. The check
+// holds the same lock as uploads, so a concurrent upload of the same
+// content either sees the file removed and stores it again, or is counted.
+func releaseAttachmentFile(ctx context.Context, att models.Attachment) {
+	db.AfterCommit(ctx, func(ctx context.Context) {
+		ctx = context.WithoutCancel(ctx)
+		err := db.Transaction(ctx, func(ctx context.Context) error {
+			if err := advisoryLock(ctx, "blob:"+att.Checksum); err != nil {
+				return err
+			}
+			var refs int64
+			if err := db.Writer(ctx).Model(&models.Attachment{}).Where("checksum = ?", att.Checksum).Count(&refs).Error; err != nil {
+				return err
+			}
+			if refs > 0 {
+				return nil
+			}
+			return blob.Default.Delete(ctx, att.StorageKey)
+		})
+		if err != nil {
+			slog.WarnContext(ctx, "Removing attachment file failed", "key", att.StorageKey, "error", err)
+		}
+	})
+}
+
+// ownerExists checks that the author or book exists. With lock it also
+// holds a key-share lock on the row until the transaction ends, which
+// blocks its deletion but not updates.
+func ownerExists(ctx context.Context, ownerType string, id uint, lock bool) error {
+	notFound := ErrBookNotFound
+	if ownerType == models.AttachmentOwnerAuthor {
+		notFound = ErrAuthorNotFound
+	}
+	tx := db.Reader(ctx)
+	if lock {
+		tx = db.Writer(ctx).Clauses(clause.Locking{Strength: "KEY SHARE"})
+	}
+	var ids []uint
+	if err := tx.Table(ownerType).Where("id = ?", id).Limit(1).Pluck("id", &ids).Error; err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return notFound
+	}
+	return nil
+}
+
+// advisoryLock takes a transaction-scoped PostgreSQL advisory lock named by key.
+func advisoryLock(ctx context.Context, key string) error {
+	return db.Writer(ctx).Exec("SELECT pg_advisory_xact_lock(hashtextextended(?, 0))", key).Error
+}
+
+func uintString(n uint) string {
+	return strconv.FormatUint(uint64(n), 10)
+}
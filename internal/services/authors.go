@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/covers"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/db"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/dto"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/models"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/outbox"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ListAuthors returns a page of authors ordered by ID.
+func ListAuthors(ctx context.Context, offset, limit int) ([]models.Author, error) {
+	var authors []models.Author
+	err := db.Reader(ctx).Order("id").Offset(offset).Limit(limit).Find(&authors).Error
+	return authors, err
+}
+
+// GetAuthor returns the author with the given ID.
+func GetAuthor(ctx context.Context, id uint) (models.Author, error) {
+	var author models.Author
+	if err := db.Reader(ctx).First(&author, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return author, ErrAuthorNotFound
+		}
+		return author, err
+	}
+	return author, nil
+}
+
+// AuthorsByIDs returns the authors with the given IDs, in no particular order.
+func AuthorsByIDs(ctx context.Context, ids []uint) ([]models.Author, error) {
+	var authors []models.Author
+	err := db.Reader(ctx).Where("id IN ?", ids).Find(&authors).Error
+	return authors, err
+}
+
+// CreateAuthor validates req and inserts a new author.
+func CreateAuthor(ctx context.Context, req dto.CreateAuthorRequest) (models.Author, error) {
+	ctx = db.WithPrimary(ctx)
+	if err := validate(req); err != nil {
+		return models.Author{}, err
+	}
+	author := req.ToModel()
+	err := db.Transaction(ctx, func(ctx context.Context) error {
+		if err := db.Writer(ctx).Create(&author).Error; err != nil {
+			return err
+		}
+		return recordChange(ctx, outbox.Author, author.ID, outbox.Created, dto.NewAuthorResponse(author))
+	})
+	return author, err
+}
+
+// UpdateAuthor applies the fields present in req to the author with the given ID.
+func UpdateAuthor(ctx context.Context, id uint, req dto.UpdateAuthorRequest) (models.Author, error) {
+	ctx = db.WithPrimary(ctx)
+	if err := validate(req); err != nil {
+		return models.Author{}, err
+	}
+	var author models.Author
+	err := db.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		if author, err = GetAuthor(ctx, id); err != nil {
+			return err
+		}
+		req.Apply(&author)
+		if err := db.Writer(ctx).Omit(clause.Associations).Save(&author).Error; err != nil {
+			return err
+		}
+		return recordChange(ctx, outbox.Author, author.ID, outbox.Updated, dto.NewAuthorResponse(author))
+	})
+	return author, err
+}
+
+// DeleteAuthor deletes the author with the given ID and returns it.
+// Books they co-wrote pass to their next credited author. Their other books
+// and reviews are removed by the foreign key cascade, along with their and
+// their books' attachments, and the books' cover files once the deletion is
+// committed.
+func DeleteAuthor(ctx context.Context, id uint) (models.Author, error) {
+	ctx = db.WithPrimary(ctx)
+	var author models.Author
+	err := db.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		if author, err = GetAuthor(ctx, id); err != nil {
+			return err
+		}
+		handedOver, err := handOverBooks(ctx, id)
+		if err != nil {
+			return err
+		}
+		var books []models.Book
+		err = db.Writer(ctx).Select("id", "cover_key").Where("author_id = ?", id).Find(&books).Error
+		if err != nil {
+			return err
+		}
+		if err := db.Writer(ctx).Delete(&author).Error; err != nil {
+			return err
+		}
+		if err := recordBookUpdates(ctx, handedOver); err != nil {
+			return err
+		}
+		// After the owners are gone, so uploads that were holding them off
+		// have committed and are deleted too
+		if err := deleteAttachments(ctx, models.AttachmentOwnerAuthor, []uint{author.ID}); err != nil {
+			return err
+		}
+		bookIDs := make([]uint, 0, len(books))
+		var coverKeys []string
+		for _, book := range books {
+			bookIDs = append(bookIDs, book.ID)
+			if book.CoverKey != "" {
+				coverKeys = append(coverKeys, book.CoverKey)
+			}
+		}
+		if err := deleteAttachments(ctx, models.AttachmentOwnerBook, bookIDs); err != nil {
+			return err
+		}
+		for _, key := range coverKeys {
+			db.AfterCommit(ctx, func(ctx context.Context) { removeBlobs(ctx, covers.Keys(key)...) })
+		}
+		return recordChange(ctx, outbox.Author, author.ID, outbox.Deleted, dto.NewAuthorResponse(author))
+	})
+	return author, err
+}
@@ -0,0 +1,229 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/covers"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/db"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/dto"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/models"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/outbox"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ListBooks returns a page of books ordered by ID.
+func ListBooks(ctx context.Context, offset, limit int) ([]models.Book, error) {
+	var books []models.Book
+	err := db.Reader(ctx).Order("id").Offset(offset).Limit(limit).Find(&books).Error
+	return books, err
+}
+
+// BooksAfter returns up to limit books with an ID greater than afterID, in
+// ID order, for walking the whole catalog in batches. A non-zero authorID
+// restricts the results to that author's books.
+func BooksAfter(ctx context.Context, afterID, authorID uint, limit int) ([]models.Book, error) {
+	tx := db.Reader(ctx).Where("id > ?", afterID)
+	if authorID != 0 {
+		tx = tx.Where("author_id = ?", authorID)
+	}
+	var books []models.Book
+	err := tx.Order("id").Limit(limit).Find(&books).Error
+	return books, err
+}
+
+// GetBook returns the book with the given ID.
+func GetBook(ctx context.Context, id uint) (models.Book, error) {
+	var book models.Book
+	if err := db.Reader(ctx).First(&book, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return book, ErrBookNotFound
+		}
+		return book, err
+	}
+	return book, nil
+}
+
+// BooksByIDs returns the books with the given IDs, in no particular order.
+func BooksByIDs(ctx context.Context, ids []uint) ([]models.Book, error) {
+	var books []models.Book
+	err := db.Reader(ctx).Where("id IN ?", ids).Find(&books).Error
+	return books, err
+}
+
+// BooksByAuthorIDs returns every book written by the given authors, ordered
+// by publication year.
+func BooksByAuthorIDs(ctx context.Context, authorIDs []uint) ([]models.Book, error) {
+	var books []models.Book
+	err := db.Reader(ctx).Where("author_id IN ?", authorIDs).
+		Order("publication_year, id").Find(&books).Error
+	return books, err
+}
+
+// CreateBook validates req and inserts a new book credited to existing
+// authors, classified under existing genres, with a first edition carrying
+// its ISBN.
+func CreateBook(ctx context.Context, req dto.CreateBookRequest) (models.Book, error) {
+	ctx = db.WithPrimary(ctx)
+	if err := validate(req); err != nil {
+		return models.Book{}, err
+	}
+	book := req.ToModel()
+
+	err := db.Transaction(ctx, func(ctx context.Context) error {
+		// Validate the contributors before inserting
+		primary, err := checkContributors(ctx, book.Contributors)
+		if err != nil {
+			return err
+		}
+		if req.AuthorID != 0 && req.AuthorID != primary {
+			return errPrimaryAuthorMismatch
+		}
+		book.AuthorID = primary
+		if book.Genres, err = findGenres(ctx, req.GenreIDs); err != nil {
+			return err
+		}
+		if err := checkSeriesPosition(ctx, book); err != nil {
+			return err
+		}
+		book.Editions = []models.Edition{{ISBN: book.ISBN}}
+		// Link the genres without writing to them
+		if err := db.Writer(ctx).Omit("Genres.*").Create(&book).Error; err != nil {
+			return err
+		}
+		return recordChange(ctx, outbox.Book, book.ID, outbox.Created, dto.NewBookResponse(book))
+	})
+	return book, err
+}
+
+// UpdateBook applies the fields present in req to the book with the given
+// ID, replacing its genres and contributors if req lists them. A new ISBN
+// is also given to its first edition.
+func UpdateBook(ctx context.Context, id uint, req dto.UpdateBookRequest) (models.Book, error) {
+	ctx = db.WithPrimary(ctx)
+	if err := validate(req); err != nil {
+		return models.Book{}, err
+	}
+	var book models.Book
+	err := db.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		if book, err = GetBook(ctx, id); err != nil {
+			return err
+		}
+		previous := book
+
+		var contributors []models.BookContributor
+		if req.Contributors != nil {
+			contributors = dto.NewContributors(req.Contributors)
+			primary, err := checkContributors(ctx, contributors)
+			if err != nil {
+				return err
+			}
+			if req.AuthorID != nil && *req.AuthorID != primary {
+				return errPrimaryAuthorMismatch
+			}
+			req.AuthorID = &primary
+		} else if req.AuthorID != nil && *req.AuthorID != book.AuthorID {
+			// Validate AuthorID if it is being changed
+			if err := authorExists(ctx, *req.AuthorID); err != nil {
+				return err
+			}
+		}
+
+		if req.SeriesID != nil && *req.SeriesID != 0 && req.SeriesPosition == nil &&
+			(previous.SeriesID == nil || *previous.SeriesID != *req.SeriesID) {
+			return &ValidationError{Err: errors.New("series_position is required when moving to another series")}
+		}
+
+		req.Apply(&book)
+		if req.SeriesPosition != nil && book.SeriesID == nil {
+			return &ValidationError{Err: errors.New("series_position requires series_id")}
+		}
+		if err := checkSeriesPosition(ctx, book); err != nil {
+			return err
+		}
+		if err := db.Writer(ctx).Omit(clause.Associations).Save(&book).Error; err != nil {
+			return err
+		}
+		if err := setBookGenres(ctx, &book, req.GenreIDs); err != nil {
+			return err
+		}
+		switch {
+		case contributors != nil:
+			err = replaceContributors(ctx, book.ID, contributors)
+		case book.AuthorID != previous.AuthorID:
+			err = replacePrimaryAuthor(ctx, book.ID, previous.AuthorID, book.AuthorID)
+		}
+		if err != nil {
+			return err
+		}
+		if book.ISBN != previous.ISBN {
+			if err := setFirstEditionISBN(ctx, book.ID, book.ISBN); err != nil {
+				return err
+			}
+		}
+		if book.Contributors, err = bookContributors(ctx, book.ID); err != nil {
+			return err
+		}
+		if book.Editions, err = bookEditions(ctx, book.ID); err != nil {
+			return err
+		}
+		return recordChange(ctx, outbox.Book, book.ID, outbox.Updated, dto.NewBookResponse(book))
+	})
+	return book, err
+}
+
+// setBookGenres replaces the book's genres with the genres with the given
+// IDs, or loads its current ones when ids is nil, so responses and events
+// always carry them.
+func setBookGenres(ctx context.Context, book *models.Book, ids []uint) error {
+	association := db.Writer(ctx).Model(book).Omit("Genres.*").Association("Genres")
+	if ids == nil {
+		return association.Find(&book.Genres)
+	}
+	genres, err := findGenres(ctx, ids)
+	if err != nil {
+		return err
+	}
+	if err := association.Replace(genres); err != nil {
+		return err
+	}
+	book.Genres = genres
+	return nil
+}
+
+// DeleteBook deletes the book with the given ID and returns it.
+// Its reviews are removed by the foreign key cascade, along with its
+// attachments, and its cover files once the deletion is committed.
+func DeleteBook(ctx context.Context, id uint) (models.Book, error) {
+	ctx = db.WithPrimary(ctx)
+	var book models.Book
+	err := db.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		if book, err = GetBook(ctx, id); err != nil {
+			return err
+		}
+		if err := db.Writer(ctx).Delete(&book).Error; err != nil {
+			return err
+		}
+		if err := deleteAttachments(ctx, models.AttachmentOwnerBook, []uint{book.ID}); err != nil {
+			return err
+		}
+		if book.CoverKey != "" {
+			db.AfterCommit(ctx, func(ctx context.Context) { removeBlobs(ctx, covers.Keys(book.CoverKey)...) })
+		}
+		return recordChange(ctx, outbox.Book, book.ID, outbox.Deleted, dto.NewBookResponse(book))
+	})
+	return book, err
+}
+
+func authorExists(ctx context.Context, id uint) error {
+	if _, err := GetAuthor(ctx, id); err != nil {
+		if errors.Is(err, ErrAuthorNotFound) {
+			return ErrInvalidAuthor
+		}
+		return err
+	}
+	return nil
+}
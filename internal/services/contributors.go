@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/db"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/dto"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/models"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/outbox"
+	"gorm.io/gorm"
+)
+
+var errPrimaryAuthorMismatch = &ValidationError{
+	Err: errors.New("author_id must be the first contributor with the author role"),
+}
+
+// checkContributors validates a book's contributors and returns its primary
+// author: the first one with the author role. Each author may hold each
+// role once, and every author must exist.
+func checkContributors(ctx context.Context, contributors []models.BookContributor) (uint, error) {
+	var primary uint
+	type credit struct {
+		authorID uint
+		role     string
+	}
+	seen := make(map[credit]bool, len(contributors))
+	ids := make([]uint, 0, len(contributors))
+	for _, c := range contributors {
+		key := credit{c.AuthorID, c.Role}
+		if seen[key] {
+			return 0, &ValidationError{Err: fmt.Errorf("author %d is listed as %s more than once", c.AuthorID, c.Role)}
+		}
+		seen[key] = true
+		ids = append(ids, c.AuthorID)
+		if primary == 0 && c.Role == models.RoleAuthor {
+			primary = c.AuthorID
+		}
+	}
+	if primary == 0 {
+		return 0, &ValidationError{Err: errors.New("at least one contributor must have the author role")}
+	}
+
+	authors, err := AuthorsByIDs(ctx, ids)
+	if err != nil {
+		return 0, err
+	}
+	found := make(map[uint]bool, len(authors))
+	for _, author := range authors {
+		found[author.ID] = true
+	}
+	for _, id := range ids {
+		if !found[id] {
+			return 0, ErrInvalidAuthor
+		}
+	}
+	return primary, nil
+}
+
+// bookContributors returns the book's contributors in credit order.
+func bookContributors(ctx context.Context, bookID uint) ([]models.BookContributor, error) {
+	var contributors []models.BookContributor
+	err := db.Writer(ctx).Where("book_id = ?", bookID).Order("position, role").Find(&contributors).Error
+	return contributors, err
+}
+
+// replaceContributors replaces all of the book's contributors.
+func replaceContributors(ctx context.Context, bookID uint, contributors []models.BookContributor) error {
+	if err := db.Writer(ctx).Where("book_id = ?", bookID).Delete(&models.BookContributor{}).Error; err != nil {
+		return err
+	}
+	for i := range contributors {
+		contributors[i].BookID = bookID
+	}
+	return db.Writer(ctx).Omit("Author").Create(&contributors).Error
+}
+
+// replacePrimaryAuthor credits newID instead of oldID as an author of the
+// book, in the same position, for clients that only change author_id. If
+// newID was already a co-author, that credit is merged into the new one.
+func replacePrimaryAuthor(ctx context.Context, bookID, oldID, newID uint) error {
+	w := db.Writer(ctx)
+	err := w.Where("book_id = ? AND author_id = ? AND role = ?", bookID, newID, models.RoleAuthor).
+		Delete(&models.BookContributor{}).Error
+	if err != nil {
+		return err
+	}
+	result := w.Model(&models.BookContributor{}).
+		Where("book_id = ? AND author_id = ? AND role = ?", bookID, oldID, models.RoleAuthor).
+		Update("author_id", newID)
+	if result.Error != nil || result.RowsAffected > 0 {
+		return result.Error
+	}
+	// The old author was not credited, so credit the new one first
+	err = w.Model(&models.BookContributor{}).Where("book_id = ?", bookID).
+		Update("position", gorm.Expr("position + 1")).Error
+	if err != nil {
+		return err
+	}
+	return w.Omit("Author").Create(&models.BookContributor{BookID: bookID, AuthorID: newID, Role: models.RoleAuthor}).Error
+}
+
+// handOverBooks makes the next credited author the primary author of the
+// books whose primary author is authorID and that have other authors, and
+// returns their IDs.
+func handOverBooks(ctx context.Context, authorID uint) ([]uint, error) {
+	var ids []uint
+	err := db.Writer(ctx).Raw(`
+        UPDATE books SET author_id = next.author_id
+        FROM (
+            SELECT DISTINCT ON (c.book_id) c.book_id, c.author_id
+            FROM book_contributors c JOIN books b ON b.id = c.book_id
+            WHERE b.author_id = ? AND c.role = ? AND c.author_id <> ?
+            ORDER BY c.book_id, c.position
+        ) AS next
+        WHERE books.id = next.book_id
+        RETURNING books.id`, authorID, models.RoleAuthor, authorID).Scan(&ids).Error
+	return ids, err
+}
+
+// recordBookUpdates records an update event for each of the books, with
+// their current contributors.
+func recordBookUpdates(ctx context.Context, ids []uint) error {
+	for _, id := range ids {
+		book, err := GetBook(ctx, id)
+		if err != nil {
+			return err
+		}
+		if book.Contributors, err = bookContributors(ctx, id); err != nil {
+			return err
+		}
+		if err := recordChange(ctx, outbox.Book, book.ID, outbox.Updated, dto.NewBookResponse(book)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/blob"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/covers"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/db"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/dto"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/models"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/outbox"
+)
+
+// SetBookCover stores data as the cover of the book with the given ID,
+// along with its thumbnails, and removes the files of the previous cover.
+// The files are stored before the book is updated, so a failure never
+// leaves the book pointing at a missing cover.
+func SetBookCover(ctx context.Context, id uint, data []byte) (models.Book, error) {
+	ctx = db.WithPrimary(ctx)
+	if _, err := GetBook(ctx, id); err != nil {
+		return models.Book{}, err
+	}
+	files, err := covers.Process(id, data)
+	if err != nil {
+		return models.Book{}, err
+	}
+	for i, f := range files {
+		if err := blob.Default.Put(ctx, f.Key, bytes.NewReader(f.Data), int64(len(f.Data)), f.ContentType); err != nil {
+			for _, stored := range files[:i] {
+				removeBlobs(ctx, stored.Key)
+			}
+			return models.Book{}, err
+		}
+	}
+
+	book, err := setCoverKey(ctx, id, files[0].Key)
+	if err != nil {
+		removeBlobs(ctx, covers.Keys(files[0].Key)...)
+	}
+	return book, err
+}
+
+// DeleteBookCover removes the cover of the book with the given ID.
+func DeleteBookCover(ctx context.Context, id uint) (models.Book, error) {
+	ctx = db.WithPrimary(ctx)
+	return setCoverKey(ctx, id, "")
+}
+
+// setCoverKey points the book at a new cover, or none for an empty key,
+// and removes the files of the previous one once that is committed.
+func setCoverKey(ctx context.Context, id uint, key string) (models.Book, error) {
+	var book models.Book
+	err := db.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		if book, err = GetBook(ctx, id); err != nil {
+			return err
+		}
+		if previous := book.CoverKey; previous != "" {
+			db.AfterCommit(ctx, func(ctx context.Context) { removeBlobs(ctx, covers.Keys(previous)...) })
+		}
+		book.CoverKey = key
+		if err := db.Writer(ctx).Model(&book).Update("cover_key", key).Error; err != nil {
+			return err
+		}
+		return recordChange(ctx, outbox.Book, book.ID, outbox.Updated, dto.NewBookResponse(book))
+	})
+	return book, err
+}
+
+// removeBlobs deletes stored files that are no longer referenced. Failures
+// only leave orphaned files behind, so they are logged, not returned.
+func removeBlobs(ctx context.Context, keys ...string) {
+	ctx = context.WithoutCancel(ctx)
+	for _, key := range keys {
+		if err := blob.Default.Delete(ctx, key); err != nil {
+			slog.WarnContext(ctx, "Removing stored file failed", "key", key, "error", err)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/db"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/dto"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrEditionNotFound = errors.New("edition not found")
+	ErrLastEdition     = errors.New("a book keeps at least one edition; delete the book instead")
+)
+
+// ListEditions returns the editions of an existing book, oldest first,
+// with their publishers.
+func ListEditions(ctx context.Context, bookID uint) ([]models.Edition, error) {
+	if _, err := GetBook(ctx, bookID); err != nil {
+		return nil, err
+	}
+	var editions []models.Edition
+	err := db.Reader(ctx).Preload("Publisher").Where("book_id = ?", bookID).Order("id").Find(&editions).Error
+	return editions, err
+}
+
+// GetEdition returns the edition with the given ID and its publisher.
+func GetEdition(ctx context.Context, id uint) (models.Edition, error) {
+	var edition models.Edition
+	if err := db.Reader(ctx).Preload("Publisher").First(&edition, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return edition, ErrEditionNotFound
+		}
+		return edition, err
+	}
+	return edition, nil
+}
+
+// CreateEdition validates req and adds an edition to the book with the
+// given ID.
+func CreateEdition(ctx context.Context, bookID uint, req dto.CreateEditionRequest) (models.Edition, error) {
+	ctx = db.WithPrimary(ctx)
+	if err := validate(req); err != nil {
+		return models.Edition{}, err
+	}
+	edition := req.ToModel(bookID)
+	err := db.Transaction(ctx, func(ctx context.Context) error {
+		if _, err := GetBook(ctx, bookID); err != nil {
+			return err
+		}
+		if err := publisherExists(ctx, edition.PublisherID); err != nil {
+			return err
+		}
+		if err := db.Writer(ctx).Omit("Publisher").Create(&edition).Error; err != nil {
+			return err
+		}
+		return loadPublisher(ctx, &edition)
+	})
+	return edition, err
+}
+
+// UpdateEdition applies the fields present in req to the edition with the
+// given ID. Changing the ISBN of a book's first edition changes the book's.
+func UpdateEdition(ctx context.Context, id uint, req dto.UpdateEditionRequest) (models.Edition, error) {
+	ctx = db.WithPrimary(ctx)
+	if err := validate(req); err != nil {
+		return models.Edition{}, err
+	}
+	var edition models.Edition
+	err := db.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		if edition, err = GetEdition(ctx, id); err != nil {
+			return err
+		}
+		req.Apply(&edition)
+		if req.PublisherID != nil {
+			if err := publisherExists(ctx, edition.PublisherID); err != nil {
+				return err
+			}
+		}
+		if err := db.Writer(ctx).Omit("Publisher").Save(&edition).Error; err != nil {
+			return err
+		}
+		if req.ISBN != nil {
+			if err := syncBookISBN(ctx, edition.BookID); err != nil {
+				return err
+			}
+		}
+		return loadPublisher(ctx, &edition)
+	})
+	return edition, err
+}
+
+// DeleteEdition deletes the edition with the given ID and returns it. If
+// it was the book's first edition, the book takes the ISBN of the next; a
+// book's last edition, whose ISBN the book has, cannot be deleted.
+func DeleteEdition(ctx context.Context, id uint) (models.Edition, error) {
+	ctx = db.WithPrimary(ctx)
+	var edition models.Edition
+	err := db.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		if edition, err = GetEdition(ctx, id); err != nil {
+			return err
+		}
+		// Lock the book so concurrent deletions count each other's
+		err = db.Writer(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").First(&models.Book{}, edition.BookID).Error
+		if err != nil {
+			return err
+		}
+		var count int64
+		if err := db.Writer(ctx).Model(&models.Edition{}).Where("book_id = ?", edition.BookID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count <= 1 {
+			return ErrLastEdition
+		}
+		if err := db.Writer(ctx).Delete(&edition).Error; err != nil {
+			return err
+		}
+		return syncBookISBN(ctx, edition.BookID)
+	})
+	return edition, err
+}
+
+// bookEditions returns the book's editions, oldest first.
+func bookEditions(ctx context.Context, bookID uint) ([]models.Edition, error) {
+	var editions []models.Edition
+	err := db.Writer(ctx).Where("book_id = ?", bookID).Order("id").Find(&editions).Error
+	return editions, err
+}
+
+// syncBookISBN sets the book's ISBN to that of its first edition, if it
+// has any editions left.
+func syncBookISBN(ctx context.Context, bookID uint) error {
+	return db.Writer(ctx).Exec(`
+        UPDATE books SET isbn = first.isbn
+        FROM (SELECT isbn FROM editions WHERE book_id = ? ORDER BY id LIMIT 1) AS first
+        WHERE books.id = ?`, bookID, bookID).Error
+}
+
+// setFirstEditionISBN sets the ISBN of the book's first edition, following
+// a change of the book's ISBN.
+func setFirstEditionISBN(ctx context.Context, bookID uint, isbn string) error {
+	return db.Writer(ctx).Exec(`
+        UPDATE editions SET isbn = ?
+        WHERE id = (SELECT id FROM editions WHERE book_id = ? ORDER BY id LIMIT 1)`, isbn, bookID).Error
+}
+
+func loadPublisher(ctx context.Context, edition *models.Edition) error {
+	edition.Publisher = models.Publisher{}
+	if edition.PublisherID == nil {
+		return nil
+	}
+	return db.Writer(ctx).First(&edition.Publisher, *edition.PublisherID).Error
+}
@@ -0,0 +1,236 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/db"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/dto"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/models"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrGenreNotFound     = errors.New("genre not found")
+	ErrInvalidGenre      = errors.New("invalid genre ID")
+	ErrInvalidGenreSlug  = errors.New("slug may only contain lowercase letters, digits and single hyphens between them")
+	ErrGenreSlugTaken    = errors.New("another genre already has this slug")
+	ErrGenreCycle        = errors.New("a genre cannot be placed under itself or one of its subgenres")
+	ErrGenreHasSubgenres = errors.New("genre has subgenres; move or delete them first")
+)
+
+var (
+	slugPattern   = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+	slugSeparator = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// subtreeQuery selects the IDs of the genres with the given IDs and all
+// their descendants.
+const subtreeQuery = `WITH RECURSIVE subtree AS (
+	SELECT id FROM genres WHERE id IN ?
+	UNION
+	SELECT genres.id FROM genres JOIN subtree ON genres.parent_id = subtree.id
+) SELECT id FROM subtree`
+
+// ListGenres returns every genre ordered by name. Each lists its parent,
+// so clients can assemble the hierarchy.
+func ListGenres(ctx context.Context) ([]models.Genre, error) {
+	var genres []models.Genre
+	err := db.Reader(ctx).Order("name, id").Find(&genres).Error
+	return genres, err
+}
+
+// GetGenre returns the genre with the given ID.
+func GetGenre(ctx context.Context, id uint) (models.Genre, error) {
+	var genre models.Genre
+	if err := db.Reader(ctx).First(&genre, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return genre, ErrGenreNotFound
+		}
+		return genre, err
+	}
+	return genre, nil
+}
+
+// GetGenreWithChildren returns the genre with the given ID and its direct
+// subgenres.
+func GetGenreWithChildren(ctx context.Context, id uint) (models.Genre, error) {
+	var genre models.Genre
+	err := db.Reader(ctx).Preload("Children", func(tx *gorm.DB) *gorm.DB {
+		return tx.Order("name, id")
+	}).First(&genre, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return genre, ErrGenreNotFound
+	}
+	return genre, err
+}
+
+// GenreIDsBySlug returns the ID of the genre with the given slug and, with
+// subgenres, the IDs of all its descendants.
+func GenreIDsBySlug(ctx context.Context, slug string, subgenres bool) ([]uint, error) {
+	var genre models.Genre
+	if err := db.Reader(ctx).Where("slug = ?", slug).First(&genre).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrGenreNotFound
+		}
+		return nil, err
+	}
+	if !subgenres {
+		return []uint{genre.ID}, nil
+	}
+	var ids []uint
+	err := db.Reader(ctx).Raw(subtreeQuery, []uint{genre.ID}).Scan(&ids).Error
+	return ids, err
+}
+
+// CreateGenre validates req and inserts a new genre, under an existing
+// parent if one is given.
+func CreateGenre(ctx context.Context, req dto.CreateGenreRequest) (models.Genre, error) {
+	ctx = db.WithPrimary(ctx)
+	if err := validate(req); err != nil {
+		return models.Genre{}, err
+	}
+	genre := req.ToModel()
+	if genre.Slug == "" {
+		genre.Slug = slugify(genre.Name)
+	}
+	err := db.Transaction(ctx, func(ctx context.Context) error {
+		if err := checkGenre(ctx, genre); err != nil {
+			return err
+		}
+		return saveGenre(db.Writer(ctx).Create(&genre).Error)
+	})
+	return genre, err
+}
+
+// UpdateGenre applies the fields present in req to the genre with the
+// given ID. A genre cannot be moved under one of its own subgenres.
+func UpdateGenre(ctx context.Context, id uint, req dto.UpdateGenreRequest) (models.Genre, error) {
+	ctx = db.WithPrimary(ctx)
+	if err := validate(req); err != nil {
+		return models.Genre{}, err
+	}
+	var genre models.Genre
+	err := db.Transaction(ctx, func(ctx context.Context) error {
+		// Moves are serialized so two concurrent ones cannot form a cycle
+		if req.ParentID != nil {
+			if err := advisoryLock(ctx, "genres:tree"); err != nil {
+				return err
+			}
+		}
+		var err error
+		if genre, err = GetGenre(ctx, id); err != nil {
+			return err
+		}
+		req.Apply(&genre)
+		if err := checkGenre(ctx, genre); err != nil {
+			return err
+		}
+		return saveGenre(db.Writer(ctx).Omit("Children").Save(&genre).Error)
+	})
+	return genre, err
+}
+
+// saveGenre maps the slug index rejecting a genre, when another request
+// took the slug after checkGenre looked, to ErrGenreSlugTaken.
+func saveGenre(err error) error {
+	if uniqueViolation(err, "idx_genres_slug") {
+		return ErrGenreSlugTaken
+	}
+	return err
+}
+
+// DeleteGenre deletes the genre with the given ID and returns it. Books
+// lose the genre; a genre with subgenres cannot be deleted.
+func DeleteGenre(ctx context.Context, id uint) (models.Genre, error) {
+	ctx = db.WithPrimary(ctx)
+	var genre models.Genre
+	err := db.Transaction(ctx, func(ctx context.Context) error {
+		if err := advisoryLock(ctx, "genres:tree"); err != nil {
+			return err
+		}
+		var err error
+		if genre, err = GetGenre(ctx, id); err != nil {
+			return err
+		}
+		var children int64
+		if err := db.Writer(ctx).Model(&models.Genre{}).Where("parent_id = ?", id).Count(&children).Error; err != nil {
+			return err
+		}
+		if children > 0 {
+			return ErrGenreHasSubgenres
+		}
+		return db.Writer(ctx).Delete(&genre).Error
+	})
+	return genre, err
+}
+
+// checkGenre validates a genre about to be saved: its slug must be well
+// formed and unique, and its parent must exist and not be the genre itself
+// or one of its descendants.
+func checkGenre(ctx context.Context, genre models.Genre) error {
+	if !slugPattern.MatchString(genre.Slug) {
+		return &ValidationError{Err: ErrInvalidGenreSlug}
+	}
+	var taken int64
+	err := db.Writer(ctx).Model(&models.Genre{}).Where("slug = ? AND id <> ?", genre.Slug, genre.ID).Count(&taken).Error
+	if err != nil {
+		return err
+	}
+	if taken > 0 {
+		return ErrGenreSlugTaken
+	}
+
+	if genre.ParentID == nil {
+		return nil
+	}
+	if _, err := GetGenre(ctx, *genre.ParentID); err != nil {
+		if errors.Is(err, ErrGenreNotFound) {
+			return &ValidationError{Err: errors.New("parent genre not found")}
+		}
+		return err
+	}
+	if genre.ID == 0 {
+		return nil
+	}
+	var ids []uint
+	if err := db.Writer(ctx).Raw(subtreeQuery, []uint{genre.ID}).Scan(&ids).Error; err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if id == *genre.ParentID {
+			return &ValidationError{Err: ErrGenreCycle}
+		}
+	}
+	return nil
+}
+
+// findGenres returns the genres with the given IDs, failing with
+// ErrInvalidGenre if any does not exist.
+func findGenres(ctx context.Context, ids []uint) ([]models.Genre, error) {
+	genres := []models.Genre{}
+	if len(ids) == 0 {
+		return genres, nil
+	}
+	if err := db.Writer(ctx).Where("id IN ?", ids).Order("name, id").Find(&genres).Error; err != nil {
+		return nil, err
+	}
+	found := make(map[uint]bool, len(genres))
+	for _, genre := range genres {
+		found[genre.ID] = true
+	}
+	for _, id := range ids {
+		if !found[id] {
+			return nil, ErrInvalidGenre
+		}
+	}
+	return genres, nil
+}
+
+// slugify derives a slug from a genre name: lowercase ASCII letters and
+// digits, with hyphens for everything in between.
+func slugify(name string) string {
+	return strings.Trim(slugSeparator.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
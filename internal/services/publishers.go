@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/db"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/dto"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/models"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrPublisherNotFound  = errors.New("publisher not found")
+	ErrInvalidPublisher   = errors.New("invalid publisher ID")
+	ErrPublisherNameTaken = errors.New("another publisher already has this name")
+)
+
+// ListPublishers returns a page of publishers ordered by name.
+func ListPublishers(ctx context.Context, offset, limit int) ([]models.Publisher, error) {
+	var publishers []models.Publisher
+	err := db.Reader(ctx).Order("name, id").Offset(offset).Limit(limit).Find(&publishers).Error
+	return publishers, err
+}
+
+// GetPublisher returns the publisher with the given ID.
+func GetPublisher(ctx context.Context, id uint) (models.Publisher, error) {
+	var publisher models.Publisher
+	if err := db.Reader(ctx).First(&publisher, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return publisher, ErrPublisherNotFound
+		}
+		return publisher, err
+	}
+	return publisher, nil
+}
+
+// CreatePublisher validates req and inserts a new publisher.
+func CreatePublisher(ctx context.Context, req dto.CreatePublisherRequest) (models.Publisher, error) {
+	ctx = db.WithPrimary(ctx)
+	if err := validate(req); err != nil {
+		return models.Publisher{}, err
+	}
+	publisher := req.ToModel()
+	err := db.Transaction(ctx, func(ctx context.Context) error {
+		if err := publisherNameFree(ctx, publisher); err != nil {
+			return err
+		}
+		return db.Writer(ctx).Create(&publisher).Error
+	})
+	return publisher, err
+}
+
+// UpdatePublisher applies the fields present in req to the publisher with
+// the given ID.
+func UpdatePublisher(ctx context.Context, id uint, req dto.UpdatePublisherRequest) (models.Publisher, error) {
+	ctx = db.WithPrimary(ctx)
+	if err := validate(req); err != nil {
+		return models.Publisher{}, err
+	}
+	var publisher models.Publisher
+	err := db.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		if publisher, err = GetPublisher(ctx, id); err != nil {
+			return err
+		}
+		req.Apply(&publisher)
+		if err := publisherNameFree(ctx, publisher); err != nil {
+			return err
+		}
+		return db.Writer(ctx).Omit("Editions").Save(&publisher).Error
+	})
+	return publisher, err
+}
+
+// DeletePublisher deletes the publisher with the given ID and returns it.
+// Its editions are kept without a publisher by the foreign key.
+func DeletePublisher(ctx context.Context, id uint) (models.Publisher, error) {
+	ctx = db.WithPrimary(ctx)
+	publisher, err := GetPublisher(ctx, id)
+	if err != nil {
+		return publisher, err
+	}
+	return publisher, db.Writer(ctx).Delete(&publisher).Error
+}
+
+func publisherNameFree(ctx context.Context, publisher models.Publisher) error {
+	var taken int64
+	err := db.Writer(ctx).Model(&models.Publisher{}).Where("name = ? AND id <> ?", publisher.Name, publisher.ID).
+		Count(&taken).Error
+	if err != nil {
+		return err
+	}
+	if taken > 0 {
+		return ErrPublisherNameTaken
+	}
+	return nil
+}
+
+func publisherExists(ctx context.Context, id *uint) error {
+	if id == nil {
+		return nil
+	}
+	if _, err := GetPublisher(ctx, *id); err != nil {
+		if errors.Is(err, ErrPublisherNotFound) {
+			return ErrInvalidPublisher
+		}
+		return err
+	}
+	return nil
+}
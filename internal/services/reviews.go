@@ -0,0 +1,224 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/db"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/dto"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/models"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/moderation"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/outbox"
+	"gorm.io/gorm"
+)
+
+var ErrReviewFlagged = errors.New("approved reviews cannot be edited into text the moderation filters flag")
+
+// ReviewFlaggedError is ErrReviewFlagged with the reasons the moderation
+// filters gave, kept apart so the message can be translated.
+type ReviewFlaggedError struct {
+	Reason string
+}
+
+func (e *ReviewFlaggedError) Error() string { return ErrReviewFlagged.Error() + ": " + e.Reason }
+
+func (e *ReviewFlaggedError) Unwrap() error { return ErrReviewFlagged }
+
+// ListReviews returns the approved reviews of an existing book.
+func ListReviews(ctx context.Context, bookID uint) ([]models.Review, error) {
+	if _, err := GetBook(ctx, bookID); err != nil {
+		return nil, err
+	}
+	var reviews []models.Review
+	err := db.Reader(ctx).Where("book_id = ? AND status = ?", bookID, models.ReviewApproved).
+		Order("id").Find(&reviews).Error
+	return reviews, err
+}
+
+// ListReviewsByStatus returns a page of the reviews with the given status,
+// oldest first, and how many there are in total.
+func ListReviewsByStatus(ctx context.Context, status string, offset, limit int) ([]models.Review, int64, error) {
+	tx := db.Reader(ctx).Model(&models.Review{}).Where("status = ?", status)
+	var total int64
+	if err := tx.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var reviews []models.Review
+	err := tx.Order("id").Offset(offset).Limit(limit).Find(&reviews).Error
+	return reviews, total, err
+}
+
+// GetReview returns the review with the given ID.
+func GetReview(ctx context.Context, id uint) (models.Review, error) {
+	var review models.Review
+	if err := db.Reader(ctx).First(&review, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return review, ErrReviewNotFound
+		}
+		return review, err
+	}
+	return review, nil
+}
+
+// ReviewsByBookIDs returns the approved reviews of the given books, best
+// rated and most recent first.
+func ReviewsByBookIDs(ctx context.Context, bookIDs []uint) ([]models.Review, error) {
+	var reviews []models.Review
+	err := db.Reader(ctx).Where("book_id IN ? AND status = ?", bookIDs, models.ReviewApproved).
+		Order("rating DESC, date_posted DESC, id").Find(&reviews).Error
+	return reviews, err
+}
+
+// CreateReview validates req and inserts a review for an existing book,
+// approved, held for a moderator or rejected by the moderation filters.
+// Only approved reviews are published to the outbox.
+func CreateReview(ctx context.Context, bookID uint, req dto.CreateReviewRequest) (models.Review, error) {
+	ctx = db.WithPrimary(ctx)
+	if err := validate(req); err != nil {
+		return models.Review{}, err
+	}
+
+	review := req.ToModel(bookID)
+	err := db.Transaction(ctx, func(ctx context.Context) error {
+		// Check if book exists
+		if _, err := GetBook(ctx, bookID); err != nil {
+			return err
+		}
+		verdict, err := moderation.Screen(ctx, review)
+		if err != nil {
+			return err
+		}
+		review.Status, review.ModerationReason = verdict.Status(), verdict.Reason()
+		review.CommentHash = moderation.Fingerprint(review.Comment)
+		if err := db.Writer(ctx).Create(&review).Error; err != nil {
+			return err
+		}
+		return recordReviewChange(ctx, "", review)
+	})
+	if err != nil {
+		return models.Review{}, err
+	}
+	return review, nil
+}
+
+// UpdateReview applies the fields present in req to the review with the given ID.
+func UpdateReview(ctx context.Context, id uint, req dto.UpdateReviewRequest) (models.Review, error) {
+	ctx = db.WithPrimary(ctx)
+	if err := validate(req); err != nil {
+		return models.Review{}, err
+	}
+	var review models.Review
+	err := db.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		if review, err = GetReview(ctx, id); err != nil {
+			return err
+		}
+		previous := review
+		req.Apply(&review)
+		if review.Comment != previous.Comment {
+			if err := rescreenReview(ctx, &review); err != nil {
+				return err
+			}
+		}
+		if err := db.Writer(ctx).Save(&review).Error; err != nil {
+			return err
+		}
+		return recordReviewChange(ctx, previous.Status, review)
+	})
+	return review, err
+}
+
+// rescreenReview runs an edited review through the moderation filters. An
+// approved review must still pass them; a held one is rejected if they
+// reject it, and a rejected one stays rejected.
+func rescreenReview(ctx context.Context, review *models.Review) error {
+	verdict, err := moderation.Screen(ctx, *review)
+	if err != nil {
+		return err
+	}
+	review.CommentHash = moderation.Fingerprint(review.Comment)
+	if verdict.Decision == moderation.Approve {
+		return nil
+	}
+	if review.Status == models.ReviewApproved {
+		return &ReviewFlaggedError{Reason: verdict.Reason()}
+	}
+	if verdict.Decision == moderation.Reject {
+		review.Status = models.ReviewRejected
+	}
+	review.ModerationReason = verdict.Reason()
+	return nil
+}
+
+// ModerateReview sets the status of the review with the given ID, as
+// decided by a moderator.
+func ModerateReview(ctx context.Context, id uint, req dto.ModerateReviewRequest) (models.Review, error) {
+	ctx = db.WithPrimary(ctx)
+	if err := validate(req); err != nil {
+		return models.Review{}, err
+	}
+	var review models.Review
+	err := db.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		if review, err = GetReview(ctx, id); err != nil {
+			return err
+		}
+		previous := review.Status
+		review.Status, review.ModerationReason = req.Status, req.Reason
+		if err := db.Writer(ctx).Save(&review).Error; err != nil {
+			return err
+		}
+		return recordReviewChange(ctx, previous, review)
+	})
+	return review, err
+}
+
+// recordReviewChange records, and publishes to webhooks, how a change to a review,
+// previously in status previous ("" if new), appears to the public: a
+// review appears when approved, changes while approved and disappears when
+// no longer approved or deleted.
+func recordReviewChange(ctx context.Context, previous string, review models.Review) error {
+	action := reviewAction(previous, review.Status)
+	if action == "" {
+		return nil
+	}
+	return recordChange(ctx, outbox.Review, review.ID, action, dto.NewReviewResponse(review))
+}
+
+// reviewAction returns the public change, outbox.Created, outbox.Updated or
+// outbox.Deleted, of a review going from status previous ("" if new) to
+// current ("" if deleted), or "" if the change is not public.
+func reviewAction(previous, current string) string {
+	was, is := previous == models.ReviewApproved, current == models.ReviewApproved
+	switch {
+	case !was && is:
+		return outbox.Created
+	case was && is:
+		return outbox.Updated
+	case was && !is:
+		return outbox.Deleted
+	default:
+		return ""
+	}
+}
+
+// DeleteReview deletes the review with the given ID and returns it. Only
+// the deletion of an approved review is recorded as an event.
+func DeleteReview(ctx context.Context, id uint) (models.Review, error) {
+	ctx = db.WithPrimary(ctx)
+	var review models.Review
+	err := db.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		if review, err = GetReview(ctx, id); err != nil {
+			return err
+		}
+		if err := db.Writer(ctx).Delete(&review).Error; err != nil {
+			return err
+		}
+		if review.Status != models.ReviewApproved {
+			return nil
+		}
+		return recordChange(ctx, outbox.Review, review.ID, outbox.Deleted, dto.NewReviewResponse(review))
+	})
+	return review, err
+}
@@ -0,0 +1,33 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/models"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/outbox"
+)
+
+func TestReviewAction(t *testing.T) {
+	tests := []struct {
+		previous, current string
+		want              string
+	}{
+		{"", models.ReviewApproved, outbox.Created},
+		{"", models.ReviewPending, ""},
+		{"", models.ReviewRejected, ""},
+		{models.ReviewPending, models.ReviewApproved, outbox.Created},
+		{models.ReviewRejected, models.ReviewApproved, outbox.Created},
+		{models.ReviewApproved, models.ReviewApproved, outbox.Updated},
+		{models.ReviewApproved, models.ReviewRejected, outbox.Deleted},
+		{models.ReviewApproved, models.ReviewPending, outbox.Deleted},
+		{models.ReviewApproved, "", outbox.Deleted},
+		{models.ReviewPending, models.ReviewRejected, ""},
+		{models.ReviewPending, models.ReviewPending, ""},
+		{models.ReviewRejected, "", ""},
+	}
+	for _, tt := range tests {
+		if got := reviewAction(tt.previous, tt.current); got != tt.want {
+			t.Errorf("reviewAction(%q, %q) = %q, want %q", tt.previous, tt.current, got, tt.want)
+		}
+	}
+}
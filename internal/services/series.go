@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/db"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/dto"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/models"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrSeriesNotFound      = errors.New("series not found")
+	ErrInvalidSeries       = errors.New("invalid series ID")
+	ErrSeriesPositionTaken = errors.New("another book already has this position in the series")
+)
+
+// ListSeries returns a page of series ordered by name.
+func ListSeries(ctx context.Context, offset, limit int) ([]models.Series, error) {
+	var series []models.Series
+	err := db.Reader(ctx).Order("name, id").Offset(offset).Limit(limit).Find(&series).Error
+	return series, err
+}
+
+// GetSeries returns the series with the given ID.
+func GetSeries(ctx context.Context, id uint) (models.Series, error) {
+	var series models.Series
+	if err := db.Reader(ctx).First(&series, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return series, ErrSeriesNotFound
+		}
+		return series, err
+	}
+	return series, nil
+}
+
+// GetSeriesWithBooks returns the series with the given ID and its books in
+// series order.
+func GetSeriesWithBooks(ctx context.Context, id uint) (models.Series, error) {
+	var series models.Series
+	err := db.Reader(ctx).Preload("Books", func(tx *gorm.DB) *gorm.DB {
+		return tx.Order("series_position")
+	}).First(&series, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return series, ErrSeriesNotFound
+	}
+	return series, err
+}
+
+// CreateSeries validates req and inserts a new series.
+func CreateSeries(ctx context.Context, req dto.CreateSeriesRequest) (models.Series, error) {
+	ctx = db.WithPrimary(ctx)
+	if err := validate(req); err != nil {
+		return models.Series{}, err
+	}
+	series := req.ToModel()
+	err := db.Writer(ctx).Create(&series).Error
+	return series, err
+}
+
+// UpdateSeries applies the fields present in req to the series with the
+// given ID.
+func UpdateSeries(ctx context.Context, id uint, req dto.UpdateSeriesRequest) (models.Series, error) {
+	ctx = db.WithPrimary(ctx)
+	if err := validate(req); err != nil {
+		return models.Series{}, err
+	}
+	series, err := GetSeries(ctx, id)
+	if err != nil {
+		return series, err
+	}
+	req.Apply(&series)
+	err = db.Writer(ctx).Omit("Books").Save(&series).Error
+	return series, err
+}
+
+// DeleteSeries deletes the series with the given ID and returns it. Its
+// books are kept, outside any series.
+func DeleteSeries(ctx context.Context, id uint) (models.Series, error) {
+	ctx = db.WithPrimary(ctx)
+	var series models.Series
+	err := db.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		if series, err = GetSeries(ctx, id); err != nil {
+			return err
+		}
+		err = db.Writer(ctx).Model(&models.Book{}).Where("series_id = ?", id).
+			Updates(map[string]interface{}{"series_id": nil, "series_position": nil}).Error
+		if err != nil {
+			return err
+		}
+		return db.Writer(ctx).Delete(&series).Error
+	})
+	return series, err
+}
+
+// checkSeriesPosition validates the series placement of a book about to be
+// saved: the series must exist and the position be free.
+func checkSeriesPosition(ctx context.Context, book models.Book) error {
+	if book.SeriesID == nil {
+		if book.SeriesPosition != nil {
+			return &ValidationError{Err: errors.New("series_position requires series_id")}
+		}
+		return nil
+	}
+	if book.SeriesPosition == nil {
+		return &ValidationError{Err: errors.New("series_position is required with series_id")}
+	}
+	if _, err := GetSeries(ctx, *book.SeriesID); err != nil {
+		if errors.Is(err, ErrSeriesNotFound) {
+			return ErrInvalidSeries
+		}
+		return err
+	}
+	var taken int64
+	err := db.Writer(ctx).Model(&models.Book{}).
+		Where("series_id = ? AND series_position = ? AND id <> ?", *book.SeriesID, *book.SeriesPosition, book.ID).
+		Count(&taken).Error
+	if err != nil {
+		return err
+	}
+	if taken > 0 {
+		return ErrSeriesPositionTaken
+	}
+	return nil
+}
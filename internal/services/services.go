@@ -0,0 +1,61 @@
+// Package services holds the validation and persistence rules for books,
+// authors and reviews, shared by the REST, GraphQL and gRPC APIs.
+//
+// Reads go to db.Reader and may be served by a replica. Mutations pin their
+// context to the primary first, so the lookups they validate against see
+// the latest data, and run in a transaction that also records the change in
+// the outbox and queues its webhook deliveries, joining the caller's
+// db.Transaction if there is one.
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/outbox"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/webhooks"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/jackc/pgconn"
+)
+
+var (
+	ErrBookNotFound   = errors.New("book not found")
+	ErrAuthorNotFound = errors.New("author not found")
+	ErrReviewNotFound = errors.New("review not found")
+	ErrInvalidAuthor  = errors.New("invalid author ID")
+)
+
+// ValidationError reports a request that failed its binding rules.
+type ValidationError struct {
+	Err error
+}
+
+func (e *ValidationError) Error() string { return e.Err.Error() }
+
+func (e *ValidationError) Unwrap() error { return e.Err }
+
+// validate applies the request's binding tags using gin's validator, so
+// every API enforces the same rules as ShouldBindJSON.
+func validate(req interface{}) error {
+	if err := binding.Validator.ValidateStruct(req); err != nil {
+		return &ValidationError{Err: err}
+	}
+	return nil
+}
+
+// recordChange records a change in the outbox and queues its webhook
+// deliveries, e.g. "book.updated", in the transaction carried by ctx, so
+// every API emits the event exactly when the change commits.
+func recordChange(ctx context.Context, aggregateType string, aggregateID uint, action string, data interface{}) error {
+	if err := outbox.Record(ctx, aggregateType, aggregateID, action, data); err != nil {
+		return err
+	}
+	return webhooks.Publish(ctx, aggregateType+"."+action, data)
+}
+
+// uniqueViolation reports whether err is Postgres rejecting a row that
+// duplicates another on the named unique index or constraint.
+func uniqueViolation(err error, constraint string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == constraint
+}
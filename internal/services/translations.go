@@ -0,0 +1,310 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/db"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/dto"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/i18n"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/models"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrTranslationNotFound = errors.New("translation not found")
+
+	errDefaultLanguage = errors.New("the default language is stored on the resource itself")
+)
+
+// ListBookTranslations returns the translations of an existing book,
+// ordered by language.
+func ListBookTranslations(ctx context.Context, bookID uint) ([]models.BookTranslation, error) {
+	if _, err := GetBook(ctx, bookID); err != nil {
+		return nil, err
+	}
+	var translations []models.BookTranslation
+	err := db.Reader(ctx).Where("book_id = ?", bookID).Order("language").Find(&translations).Error
+	return translations, err
+}
+
+// PutBookTranslation validates req and sets the book's title and
+// description in the given language, replacing any previous translation.
+func PutBookTranslation(ctx context.Context, bookID uint, lang string, req dto.BookTranslationRequest) (models.BookTranslation, error) {
+	ctx = db.WithPrimary(ctx)
+	if err := validate(req); err != nil {
+		return models.BookTranslation{}, err
+	}
+	lang, err := translationLanguage(lang)
+	if err != nil {
+		return models.BookTranslation{}, err
+	}
+	translation := req.ToModel(bookID, lang)
+	err = db.Transaction(ctx, func(ctx context.Context) error {
+		if _, err := GetBook(ctx, bookID); err != nil {
+			return err
+		}
+		return db.Writer(ctx).Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "book_id"}, {Name: "language"}},
+			DoUpdates: clause.AssignmentColumns([]string{"title", "description"}),
+		}).Create(&translation).Error
+	})
+	return translation, err
+}
+
+// DeleteBookTranslation deletes the book's translation into the given
+// language and returns it.
+func DeleteBookTranslation(ctx context.Context, bookID uint, lang string) (models.BookTranslation, error) {
+	ctx = db.WithPrimary(ctx)
+	var translation models.BookTranslation
+	lang, err := i18n.Canonical(lang)
+	if err != nil {
+		return translation, ErrTranslationNotFound
+	}
+	err = db.Transaction(ctx, func(ctx context.Context) error {
+		if _, err := GetBook(ctx, bookID); err != nil {
+			return err
+		}
+		result := db.Writer(ctx).Clauses(clause.Returning{}).
+			Where("book_id = ? AND language = ?", bookID, lang).Delete(&translation)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrTranslationNotFound
+		}
+		return nil
+	})
+	return translation, err
+}
+
+// ListAuthorTranslations returns the translations of an existing author,
+// ordered by language.
+func ListAuthorTranslations(ctx context.Context, authorID uint) ([]models.AuthorTranslation, error) {
+	if _, err := GetAuthor(ctx, authorID); err != nil {
+		return nil, err
+	}
+	var translations []models.AuthorTranslation
+	err := db.Reader(ctx).Where("author_id = ?", authorID).Order("language").Find(&translations).Error
+	return translations, err
+}
+
+// PutAuthorTranslation validates req and sets the author's biography in the
+// given language, replacing any previous translation.
+func PutAuthorTranslation(ctx context.Context, authorID uint, lang string, req dto.AuthorTranslationRequest) (models.AuthorTranslation, error) {
+	ctx = db.WithPrimary(ctx)
+	if err := validate(req); err != nil {
+		return models.AuthorTranslation{}, err
+	}
+	lang, err := translationLanguage(lang)
+	if err != nil {
+		return models.AuthorTranslation{}, err
+	}
+	translation := req.ToModel(authorID, lang)
+	err = db.Transaction(ctx, func(ctx context.Context) error {
+		if _, err := GetAuthor(ctx, authorID); err != nil {
+			return err
+		}
+		return db.Writer(ctx).Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "author_id"}, {Name: "language"}},
+			DoUpdates: clause.AssignmentColumns([]string{"biography"}),
+		}).Create(&translation).Error
+	})
+	return translation, err
+}
+
+// DeleteAuthorTranslation deletes the author's translation into the given
+// language and returns it.
+func DeleteAuthorTranslation(ctx context.Context, authorID uint, lang string) (models.AuthorTranslation, error) {
+	ctx = db.WithPrimary(ctx)
+	var translation models.AuthorTranslation
+	lang, err := i18n.Canonical(lang)
+	if err != nil {
+		return translation, ErrTranslationNotFound
+	}
+	err = db.Transaction(ctx, func(ctx context.Context) error {
+		if _, err := GetAuthor(ctx, authorID); err != nil {
+			return err
+		}
+		result := db.Writer(ctx).Clauses(clause.Returning{}).
+			Where("author_id = ? AND language = ?", authorID, lang).Delete(&translation)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrTranslationNotFound
+		}
+		return nil
+	})
+	return translation, err
+}
+
+// translationLanguage returns the canonical form of a translation's
+// language tag, which cannot be the default language.
+func translationLanguage(lang string) (string, error) {
+	canonical, err := i18n.Canonical(lang)
+	if err != nil {
+		return "", &ValidationError{Err: fmt.Errorf("invalid language %q: %w", lang, err)}
+	}
+	if canonical == i18n.DefaultLanguage() {
+		return "", &ValidationError{Err: errDefaultLanguage}
+	}
+	return canonical, nil
+}
+
+// LocalizeBooks serves the books, and their preloaded authors, in the
+// languages the client prefers where they have translations.
+func LocalizeBooks(ctx context.Context, books []models.Book) error {
+	l := newLocalizer()
+	for i := range books {
+		l.addBook(&books[i])
+	}
+	return l.apply(ctx)
+}
+
+// LocalizeBook is LocalizeBooks for a single book.
+func LocalizeBook(ctx context.Context, book *models.Book) error {
+	l := newLocalizer()
+	l.addBook(book)
+	return l.apply(ctx)
+}
+
+// LocalizeAuthors serves the authors, and their preloaded books, in the
+// languages the client prefers where they have translations.
+func LocalizeAuthors(ctx context.Context, authors []models.Author) error {
+	l := newLocalizer()
+	for i := range authors {
+		l.addAuthor(&authors[i])
+	}
+	return l.apply(ctx)
+}
+
+// LocalizeAuthor is LocalizeAuthors for a single author.
+func LocalizeAuthor(ctx context.Context, author *models.Author) error {
+	l := newLocalizer()
+	l.addAuthor(author)
+	return l.apply(ctx)
+}
+
+// localizer collects every book and author in a response, however deeply
+// nested, so their translations are looked up in one query per table.
+type localizer struct {
+	books   map[uint][]*models.Book
+	authors map[uint][]*models.Author
+}
+
+func newLocalizer() *localizer {
+	return &localizer{books: map[uint][]*models.Book{}, authors: map[uint][]*models.Author{}}
+}
+
+func (l *localizer) addBook(book *models.Book) {
+	l.books[book.ID] = append(l.books[book.ID], book)
+	if book.Author.ID != 0 {
+		l.addAuthor(&book.Author)
+	}
+	for i := range book.Contributors {
+		if book.Contributors[i].Author.ID != 0 {
+			l.addAuthor(&book.Contributors[i].Author)
+		}
+	}
+}
+
+func (l *localizer) addAuthor(author *models.Author) {
+	l.authors[author.ID] = append(l.authors[author.ID], author)
+	for i := range author.Books {
+		l.addBook(&author.Books[i])
+	}
+}
+
+// apply replaces the fields of the collected books and authors with their
+// chosen translations and records the language each is served in. Fields
+// a translation leaves empty keep the default language.
+func (l *localizer) apply(ctx context.Context) error {
+	bookTranslations := map[uint][]models.BookTranslation{}
+	authorTranslations := map[uint][]models.AuthorTranslation{}
+	if len(i18n.Preferred(ctx)) > 0 {
+		if len(l.books) > 0 {
+			var rows []models.BookTranslation
+			if err := db.Reader(ctx).Where("book_id IN ?", mapKeys(l.books)).Find(&rows).Error; err != nil {
+				return err
+			}
+			for _, t := range rows {
+				bookTranslations[t.BookID] = append(bookTranslations[t.BookID], t)
+			}
+		}
+		if len(l.authors) > 0 {
+			var rows []models.AuthorTranslation
+			if err := db.Reader(ctx).Where("author_id IN ?", mapKeys(l.authors)).Find(&rows).Error; err != nil {
+				return err
+			}
+			for _, t := range rows {
+				authorTranslations[t.AuthorID] = append(authorTranslations[t.AuthorID], t)
+			}
+		}
+	}
+
+	for id, books := range l.books {
+		translation, lang := chooseBookTranslation(ctx, bookTranslations[id])
+		for _, book := range books {
+			book.Language = lang
+			if translation == nil {
+				continue
+			}
+			if translation.Title != "" {
+				book.Title = translation.Title
+			}
+			if translation.Description != "" {
+				book.Description = translation.Description
+			}
+		}
+	}
+	for id, authors := range l.authors {
+		translation, lang := chooseAuthorTranslation(ctx, authorTranslations[id])
+		for _, author := range authors {
+			author.Language = lang
+			if translation != nil && translation.Biography != "" {
+				author.Biography = translation.Biography
+			}
+		}
+	}
+	return nil
+}
+
+func chooseBookTranslation(ctx context.Context, translations []models.BookTranslation) (*models.BookTranslation, string) {
+	languages := make([]string, 0, len(translations))
+	for _, t := range translations {
+		languages = append(languages, t.Language)
+	}
+	if lang := i18n.Choose(ctx, languages); lang != "" {
+		for i := range translations {
+			if translations[i].Language == lang {
+				return &translations[i], lang
+			}
+		}
+	}
+	return nil, i18n.DefaultLanguage()
+}
+
+func chooseAuthorTranslation(ctx context.Context, translations []models.AuthorTranslation) (*models.AuthorTranslation, string) {
+	languages := make([]string, 0, len(translations))
+	for _, t := range translations {
+		languages = append(languages, t.Language)
+	}
+	if lang := i18n.Choose(ctx, languages); lang != "" {
+		for i := range translations {
+			if translations[i].Language == lang {
+				return &translations[i], lang
+			}
+		}
+	}
+	return nil, i18n.DefaultLanguage()
+}
+
+func mapKeys[V any](m map[uint]V) []uint {
+	keys := make([]uint, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
@@ -0,0 +1,71 @@
+package tracing
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/i18n"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TraceIDHeader returns the trace ID to clients so they can quote it when
+// reporting a problem.
+const TraceIDHeader = "X-Trace-ID"
+
+// Middleware starts a server span for each request, continuing the trace
+// from the incoming traceparent header if there is one, and stores it in
+// the request context so database and Redis spans become its children.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := otel.GetTextMapPropagator().Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+
+		route := c.FullPath()
+		name := c.Request.Method
+		if route != "" {
+			name += " " + route
+		}
+		ctx, span := tracer.Start(ctx, name,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(c.Request.Method),
+				semconv.HTTPRoute(route),
+				semconv.URLPath(c.Request.URL.Path),
+				semconv.ClientAddress(c.ClientIP()),
+				semconv.UserAgentOriginal(c.Request.UserAgent()),
+			),
+		)
+		defer span.End()
+
+		if id := TraceID(ctx); id != "" {
+			c.Header(TraceIDHeader, id)
+		}
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		status := c.Writer.Status()
+		span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+		if len(c.Errors) > 0 {
+			span.RecordError(fmt.Errorf("%s", c.Errors.String()))
+		}
+	}
+}
+
+// ErrorBody is the JSON body of an error response, with the message in the
+// client's language when it has a translation. It carries the trace ID when
+// the request is traced so the failure can be found in the tracing backend.
+func ErrorBody(c *gin.Context, message string) gin.H {
+	body := gin.H{"error": i18n.Message(c.Request.Context(), message)}
+	if id := TraceID(c.Request.Context()); id != "" {
+		body["trace_id"] = id
+	}
+	return body
+}
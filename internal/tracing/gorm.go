@@ -0,0 +1,82 @@
+package tracing
+
+import (
+	"errors"
+	"strings"
+
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const gormSpanKey = "tracing:span"
+
+// GormPlugin records a client span for every GORM statement, named after
+// the SQL operation and table, e.g. "SELECT books". Register it with
+// db.Use(tracing.GormPlugin{}).
+type GormPlugin struct{}
+
+func (GormPlugin) Name() string {
+	return "tracing"
+}
+
+func (GormPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	hooks := []struct {
+		register func(name string, fn func(*gorm.DB)) error
+		after    func(name string, fn func(*gorm.DB)) error
+	}{
+		{cb.Create().Before("gorm:create").Register, cb.Create().After("gorm:create").Register},
+		{cb.Query().Before("gorm:query").Register, cb.Query().After("gorm:query").Register},
+		{cb.Update().Before("gorm:update").Register, cb.Update().After("gorm:update").Register},
+		{cb.Delete().Before("gorm:delete").Register, cb.Delete().After("gorm:delete").Register},
+		{cb.Row().Before("gorm:row").Register, cb.Row().After("gorm:row").Register},
+		{cb.Raw().Before("gorm:raw").Register, cb.Raw().After("gorm:raw").Register},
+	}
+	for _, h := range hooks {
+		if err := h.register("tracing:before", startGormSpan); err != nil {
+			return err
+		}
+		if err := h.after("tracing:after", endGormSpan); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func startGormSpan(db *gorm.DB) {
+	ctx, span := tracer.Start(db.Statement.Context, "gorm",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemNamePostgreSQL),
+	)
+	db.Statement.Context = ctx
+	db.InstanceSet(gormSpanKey, span)
+}
+
+func endGormSpan(db *gorm.DB) {
+	value, ok := db.InstanceGet(gormSpanKey)
+	if !ok {
+		return
+	}
+	span := value.(trace.Span)
+	defer span.End()
+
+	query := db.Statement.SQL.String()
+	operation := strings.ToUpper(strings.SplitN(strings.TrimSpace(query), " ", 2)[0])
+	name := operation
+	if db.Statement.Table != "" {
+		name += " " + db.Statement.Table
+		span.SetAttributes(semconv.DBCollectionName(db.Statement.Table))
+	}
+	span.SetName(name)
+	span.SetAttributes(semconv.DBOperationName(operation), semconv.DBQueryText(query))
+	if operation == "SELECT" {
+		span.SetAttributes(semconv.DBResponseReturnedRows(int(db.Statement.RowsAffected)))
+	}
+
+	if err := db.Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
@@ -0,0 +1,16 @@
+package webhooks
+
+import (
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/auth"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/config"
+	"github.com/gin-gonic/gin"
+)
+
+// RequireAdmin guards the subscription endpoints: it answers 401 to
+// requests without a bearer token and 403 to those whose token is not one
+// of cfg.AdminTokens.
+func RequireAdmin(cfg config.WebhooksConfig) gin.HandlerFunc {
+	return auth.Require(func(c *gin.Context) bool {
+		return auth.HasToken(c, cfg.AdminTokens)
+	}, "An admin token is required", "Only admins may do this")
+}
@@ -0,0 +1,103 @@
+package webhooks
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/config"
+)
+
+// ErrPrivateDestination is returned for webhook URLs, and deliveries, that
+// would reach a loopback, private, link-local or otherwise non-public host.
+var ErrPrivateDestination = errors.New("webhook URLs must point to a public host")
+
+// allowPrivate disables the destination checks, for local development.
+var allowPrivate bool
+
+// nonPublic lists the special-purpose ranges, besides those netip.Addr
+// classifies, that deliveries must not reach.
+var nonPublic = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+	netip.MustParsePrefix("64:ff9b:1::/48"),
+}
+
+// isPublic reports whether addr is a globally routable unicast address.
+func isPublic(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+	for _, p := range nonPublic {
+		if p.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckURL returns ErrPrivateDestination for URLs naming a non-public IP
+// address or localhost. Host names are only resolved when delivering, so
+// the delivery client checks the addresses they resolve to as well.
+func CheckURL(raw string) error {
+	if allowPrivate {
+		return nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return ErrPrivateDestination
+	}
+	if addr, err := netip.ParseAddr(host); err == nil && !isPublic(addr) {
+		return ErrPrivateDestination
+	}
+	return nil
+}
+
+// checkDial is the delivery dialer's Control function: it runs after host
+// names are resolved, so it also stops names that resolve, or are rebound,
+// to non-public addresses.
+func checkDial(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	if !isPublic(addrPort.Addr()) {
+		return fmt.Errorf("%w, not %s", ErrPrivateDestination, addrPort.Addr())
+	}
+	return nil
+}
+
+// newClient returns the HTTP client deliveries are sent with. It does not
+// use proxies, so the dialer sees the real destination of every request,
+// redirects included.
+func newClient(cfg config.WebhooksConfig) *http.Client {
+	dialer := &net.Dialer{Timeout: cfg.Timeout, KeepAlive: 30 * time.Second}
+	if !cfg.AllowPrivateDestinations {
+		dialer.Control = checkDial
+	}
+	return &http.Client{
+		Timeout: cfg.Timeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			ForceAttemptHTTP2:   true,
+			MaxIdleConns:        100,
+			IdleConnTimeout:     90 * time.Second,
+			TLSHandshakeTimeout: 10 * time.Second,
+		},
+	}
+}
@@ -0,0 +1,277 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/config"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/db"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Delivery request headers.
+const (
+	EventHeader     = "X-Webhook-Event"
+	IDHeader        = "X-Webhook-ID"
+	TimestampHeader = "X-Webhook-Timestamp"
+	SignatureHeader = "X-Webhook-Signature"
+)
+
+// claimBatch is how many due deliveries a poll picks up at most.
+const claimBatch = 20
+
+// envelope is the JSON body of every delivery.
+type envelope struct {
+	ID        string      `json:"id"`
+	Type      string      `json:"type"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+var (
+	wake    = make(chan struct{}, 1)
+	stop    = make(chan struct{})
+	running sync.WaitGroup
+)
+
+// Publish queues event for every active subscription that selected it. The
+// deliveries are written with db.Writer(ctx), so inside db.Transaction they
+// are only sent if the transaction commits.
+func Publish(ctx context.Context, event string, data interface{}) error {
+	var subs []models.WebhookSubscription
+	if err := db.Writer(ctx).Where("active = ?", true).Find(&subs).Error; err != nil {
+		return err
+	}
+
+	eventID := newEventID()
+	var payload []byte
+	var deliveries []models.WebhookDelivery
+	for _, sub := range subs {
+		if !subscribed(sub, event) {
+			continue
+		}
+		if payload == nil {
+			var err error
+			payload, err = json.Marshal(envelope{ID: eventID, Type: event, CreatedAt: time.Now().UTC(), Data: data})
+			if err != nil {
+				return err
+			}
+		}
+		deliveries = append(deliveries, newDelivery(sub.ID, eventID, event, string(payload)))
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+	if err := db.Writer(ctx).Create(&deliveries).Error; err != nil {
+		return err
+	}
+	db.AfterCommit(ctx, func(context.Context) { notify() })
+	return nil
+}
+
+func subscribed(sub models.WebhookSubscription, event string) bool {
+	for _, e := range sub.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+func newDelivery(subscriptionID uint, eventID, event, payload string) models.WebhookDelivery {
+	now := time.Now()
+	return models.WebhookDelivery{
+		SubscriptionID: subscriptionID,
+		EventID:        eventID,
+		Event:          event,
+		Payload:        payload,
+		Status:         models.DeliveryPending,
+		NextAttemptAt:  &now,
+	}
+}
+
+func newEventID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// notify wakes the worker without waiting for the next poll.
+func notify() {
+	select {
+	case wake <- struct{}{}:
+	default:
+	}
+}
+
+// Start runs the delivery worker until Stop is called. It polls for due
+// deliveries every cfg.PollInterval and whenever one is published.
+func Start(cfg config.WebhooksConfig) {
+	allowPrivate = cfg.AllowPrivateDestinations
+	client := newClient(cfg)
+	running.Add(1)
+	go func() {
+		defer running.Done()
+		ticker := time.NewTicker(cfg.PollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			case <-wake:
+			}
+			if err := deliverDue(client, cfg); err != nil {
+				slog.Error("Webhook delivery poll failed", "error", err)
+			}
+		}
+	}()
+}
+
+// Stop stops the worker, waiting for in-flight deliveries to finish.
+// Deliveries it had claimed but not sent are retried on the next start.
+func Stop() {
+	close(stop)
+	running.Wait()
+}
+
+// deliverDue claims the due deliveries and sends them concurrently. Claimed
+// rows are pushed past the delivery timeout so other instances skip them
+// while they are in flight.
+func deliverDue(client *http.Client, cfg config.WebhooksConfig) error {
+	ctx := context.Background()
+	var due []models.WebhookDelivery
+	err := db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", models.DeliveryPending, now).
+			Order("next_attempt_at").Limit(claimBatch).Find(&due).Error
+		if err != nil || len(due) == 0 {
+			return err
+		}
+		ids := make([]uint, len(due))
+		for i, d := range due {
+			ids[i] = d.ID
+		}
+		return tx.Model(&models.WebhookDelivery{}).Where("id IN ?", ids).
+			Update("next_attempt_at", now.Add(2*cfg.Timeout)).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	for _, d := range due {
+		wg.Add(1)
+		go func(d models.WebhookDelivery) {
+			defer wg.Done()
+			attempt(ctx, client, cfg, d)
+		}(d)
+	}
+	wg.Wait()
+	return nil
+}
+
+// attempt sends one delivery and records the outcome, scheduling a retry
+// or giving up after cfg.MaxAttempts.
+func attempt(ctx context.Context, client *http.Client, cfg config.WebhooksConfig, d models.WebhookDelivery) {
+	var sub models.WebhookSubscription
+	if err := db.DB.WithContext(ctx).First(&sub, d.SubscriptionID).Error; err != nil {
+		slog.Error("Webhook subscription lookup failed", "delivery_id", d.ID, "error", err)
+		return
+	}
+
+	status, err := send(ctx, client, sub, d)
+	now := time.Now()
+	d.Attempts++
+	d.ResponseStatus = status
+	switch {
+	case err == nil && status >= 200 && status < 300:
+		d.Status = models.DeliverySucceeded
+		d.LastError = ""
+		d.DeliveredAt = &now
+		d.NextAttemptAt = nil
+	default:
+		if err != nil {
+			d.LastError = err.Error()
+		} else {
+			d.LastError = fmt.Sprintf("HTTP %d", status)
+		}
+		if d.Attempts >= cfg.MaxAttempts {
+			d.Status = models.DeliveryFailed
+			d.NextAttemptAt = nil
+		} else {
+			next := now.Add(retryDelay(d.Attempts, cfg.RetryBackoff, cfg.RetryMaxBackoff))
+			d.NextAttemptAt = &next
+		}
+		slog.Warn("Webhook delivery failed", "delivery_id", d.ID, "event", d.Event,
+			"attempt", d.Attempts, "status", d.Status, "error", d.LastError)
+	}
+
+	if err := db.DB.WithContext(ctx).Save(&d).Error; err != nil {
+		slog.Error("Recording webhook delivery failed", "delivery_id", d.ID, "error", err)
+	}
+}
+
+// send POSTs the delivery's payload, signed with the subscription secret,
+// and returns the response status. The response body is discarded, so the
+// delivery log never exposes what the receiver answered.
+func send(ctx context.Context, client *http.Client, sub models.WebhookSubscription, d models.WebhookDelivery) (int, error) {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader([]byte(d.Payload)))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "book-library-webhooks/1.0")
+	req.Header.Set(EventHeader, d.Event)
+	req.Header.Set(IDHeader, d.EventID)
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(SignatureHeader, Sign(sub.Secret, timestamp, []byte(d.Payload)))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	return resp.StatusCode, nil
+}
+
+// Sign returns the X-Webhook-Signature value for a payload: "sha256=" and
+// the hex HMAC-SHA256, keyed with the secret, of the timestamp, a dot and
+// the body. Receivers recompute it to authenticate the delivery and reject
+// old timestamps to prevent replays.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// retryDelay returns the wait after the given number of failed attempts:
+// base doubled per attempt, capped at max.
+func retryDelay(attempts int, base, max time.Duration) time.Duration {
+	delay := base
+	for i := 1; i < attempts && delay < max; i++ {
+		delay *= 2
+	}
+	if delay > max {
+		delay = max
+	}
+	return delay
+}
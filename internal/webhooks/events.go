@@ -0,0 +1,25 @@
+// Package webhooks delivers catalog and review events to subscribed URLs.
+// Publishing an event records one delivery per matching subscription in the
+// database; a background worker sends them as signed POST requests and
+// retries failures with exponential backoff.
+package webhooks
+
+// Event types.
+const (
+	AuthorCreated = "author.created"
+	AuthorUpdated = "author.updated"
+	AuthorDeleted = "author.deleted"
+	BookCreated   = "book.created"
+	BookUpdated   = "book.updated"
+	BookDeleted   = "book.deleted"
+	ReviewCreated = "review.created"
+	ReviewUpdated = "review.updated"
+	ReviewDeleted = "review.deleted"
+)
+
+// Events lists every event type a subscription can select.
+var Events = []string{
+	AuthorCreated, AuthorUpdated, AuthorDeleted,
+	BookCreated, BookUpdated, BookDeleted,
+	ReviewCreated, ReviewUpdated, ReviewDeleted,
+}
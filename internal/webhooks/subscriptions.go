@@ -0,0 +1,117 @@
+package webhooks
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/db"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/models"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrSubscriptionNotFound = errors.New("webhook subscription not found")
+	ErrDeliveryNotFound     = errors.New("webhook delivery not found")
+)
+
+// ListSubscriptions returns every subscription ordered by ID.
+func ListSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error) {
+	var subs []models.WebhookSubscription
+	err := db.Reader(ctx).Order("id").Find(&subs).Error
+	return subs, err
+}
+
+// GetSubscription returns the subscription with the given ID.
+func GetSubscription(ctx context.Context, id uint) (models.WebhookSubscription, error) {
+	var sub models.WebhookSubscription
+	if err := db.Reader(ctx).First(&sub, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return sub, ErrSubscriptionNotFound
+		}
+		return sub, err
+	}
+	return sub, nil
+}
+
+// CreateSubscription stores sub, generating a secret if it has none.
+func CreateSubscription(ctx context.Context, sub models.WebhookSubscription) (models.WebhookSubscription, error) {
+	if err := CheckURL(sub.URL); err != nil {
+		return sub, err
+	}
+	if sub.Secret == "" {
+		sub.Secret = newSecret()
+	}
+	sub.Active = true
+	err := db.Writer(ctx).Create(&sub).Error
+	return sub, err
+}
+
+// UpdateSubscription applies apply to the subscription with the given ID
+// and stores the result.
+func UpdateSubscription(ctx context.Context, id uint, apply func(*models.WebhookSubscription)) (models.WebhookSubscription, error) {
+	ctx = db.WithPrimary(ctx)
+	sub, err := GetSubscription(ctx, id)
+	if err != nil {
+		return sub, err
+	}
+	apply(&sub)
+	if err := CheckURL(sub.URL); err != nil {
+		return sub, err
+	}
+	err = db.Writer(ctx).Save(&sub).Error
+	return sub, err
+}
+
+// DeleteSubscription deletes the subscription with the given ID. Its
+// deliveries are removed by the foreign key cascade.
+func DeleteSubscription(ctx context.Context, id uint) error {
+	ctx = db.WithPrimary(ctx)
+	sub, err := GetSubscription(ctx, id)
+	if err != nil {
+		return err
+	}
+	return db.Writer(ctx).Delete(&sub).Error
+}
+
+// ListDeliveries returns a page of a subscription's deliveries, newest
+// first, and their total count.
+func ListDeliveries(ctx context.Context, subscriptionID uint, offset, limit int) ([]models.WebhookDelivery, int64, error) {
+	var total int64
+	tx := db.Reader(ctx).Model(&models.WebhookDelivery{}).Where("subscription_id = ?", subscriptionID)
+	if err := tx.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var deliveries []models.WebhookDelivery
+	err := db.Reader(ctx).Where("subscription_id = ?", subscriptionID).
+		Order("id DESC").Offset(offset).Limit(limit).Find(&deliveries).Error
+	return deliveries, total, err
+}
+
+// Redeliver queues a new delivery of the same event and payload as the
+// given delivery of the subscription, leaving the original in the log.
+func Redeliver(ctx context.Context, subscriptionID, deliveryID uint) (models.WebhookDelivery, error) {
+	ctx = db.WithPrimary(ctx)
+	var original models.WebhookDelivery
+	err := db.Reader(ctx).Where("subscription_id = ?", subscriptionID).First(&original, deliveryID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return original, ErrDeliveryNotFound
+		}
+		return original, err
+	}
+
+	delivery := newDelivery(subscriptionID, original.EventID, original.Event, original.Payload)
+	if err := db.Writer(ctx).Create(&delivery).Error; err != nil {
+		return delivery, err
+	}
+	notify()
+	return delivery, nil
+}
+
+func newSecret() string {
+	b := make([]byte, 32)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -2,22 +2,41 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"net"
+	"net/http"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 	"time"
 
 	_ "github.com/MentalArts/go-rest-api-mehmet-pala/docs"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/blob"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/cache"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/config"
 	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/db"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/events"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/grpcserver"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/handlers"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/health"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/i18n"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/logging"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/moderation"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/outbox"
 	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/routes"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/tracing"
+	"github.com/MentalArts/go-rest-api-mehmet-pala/internal/webhooks"
 	"github.com/gin-gonic/gin"
-	"github.com/go-redis/redis/v8"
-	"github.com/joho/godotenv"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"google.golang.org/grpc"
+	grpchealth "google.golang.org/grpc/health"
 )
 
 // Prometheus metrics
@@ -31,55 +50,50 @@ var (
 	)
 )
 
-// Redis client
-var rdb *redis.Client
-
-func initRedis() {
-	rdb = redis.NewClient(&redis.Options{
-		Addr:     fmt.Sprintf("%s:%s", os.Getenv("REDIS_HOST"), os.Getenv("REDIS_PORT")),
-		Password: os.Getenv("REDIS_PASSWORD"),
-		DB:       0,
-	})
-	pong, err := rdb.Ping(context.Background()).Result()
-	log.Printf("Redis ping: %s, error: %v", pong, err)
+// unlimitedPaths are polled by orchestrators and Prometheus and must not be
+// rate-limited.
+var unlimitedPaths = map[string]bool{
+	"/livez":   true,
+	"/readyz":  true,
+	"/health":  true,
+	"/metrics": true,
 }
 
 // Rate limiting middleware
 func rateLimitMiddleware(limit int, window time.Duration) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Skip rate-limiting for Swagger routes
-		if strings.HasPrefix(c.Request.URL.Path, "/swagger/") {
+		// Skip rate-limiting for Swagger, probes and metrics
+		path := c.Request.URL.Path
+		if strings.HasPrefix(path, "/swagger/") || unlimitedPaths[path] {
 			c.Next()
 			return
 		}
 
 		ip := c.ClientIP() // Get client IP
-		ctx := context.Background()
+		ctx := c.Request.Context()
 		key := fmt.Sprintf("rate_limit:%s", ip)
 
 		// Increment count atomically and set expiration if new key
-		count, err := rdb.Incr(ctx, key).Result()
+		count, err := cache.RDB.Incr(ctx, key).Result()
 		if err != nil {
-			log.Printf("Error incrementing Redis count: %v", err)
-			c.JSON(500, gin.H{"error": "Internal server error"})
-			c.Abort()
+			slog.ErrorContext(ctx, "Error incrementing Redis count", "error", err)
+			abortRedisError(c, err)
 			return
 		}
 
 		// If this is the first request, set expiration
 		if count == 1 {
-			_, err = rdb.Expire(ctx, key, window).Result()
+			_, err = cache.RDB.Expire(ctx, key, window).Result()
 			if err != nil {
-				log.Printf("Error setting Redis expiration: %v", err)
-				c.JSON(500, gin.H{"error": "Internal server error"})
-				c.Abort()
+				slog.ErrorContext(ctx, "Error setting Redis expiration", "error", err)
+				abortRedisError(c, err)
 				return
 			}
 		}
 
 		// Check if limit exceeded
 		if count > int64(limit) {
-			c.JSON(429, gin.H{"error": "Rate limit exceeded"})
+			c.JSON(429, tracing.ErrorBody(c, "Rate limit exceeded"))
 			c.Abort()
 			return
 		}
@@ -89,15 +103,94 @@ func rateLimitMiddleware(limit int, window time.Duration) gin.HandlerFunc {
 	}
 }
 
-func setCache(key string, value string) {
-	err := rdb.Set(context.Background(), key, value, 0).Err()
+// abortRedisError aborts a request whose Redis call failed: 504 if the
+// request deadline passed, 503 if the request was cancelled and 500
+// otherwise.
+func abortRedisError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		c.AbortWithStatusJSON(http.StatusGatewayTimeout, tracing.ErrorBody(c, "Request timed out"))
+	case errors.Is(err, context.Canceled):
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, tracing.ErrorBody(c, "Request cancelled"))
+	default:
+		c.AbortWithStatusJSON(500, tracing.ErrorBody(c, "Internal server error"))
+	}
+}
+
+// timeoutMiddleware bounds every request with a deadline so database and
+// Redis calls made with the request context are cancelled once it passes.
+// A handler that gives up because of it responds 504 itself; if nothing was
+// written by the time the chain returns, 504 is sent here. Routes whose
+// pattern is listed in exempt, such as event streams and file transfers,
+// run without one.
+func timeoutMiddleware(timeout time.Duration, exempt ...string) gin.HandlerFunc {
+	skip := make(map[string]bool, len(exempt))
+	for _, path := range exempt {
+		skip[path] = true
+	}
+	return func(c *gin.Context) {
+		if timeout <= 0 || skip[c.FullPath()] {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) && !c.Writer.Written() {
+			c.JSON(http.StatusGatewayTimeout, tracing.ErrorBody(c, "Request timed out"))
+		}
+	}
+}
+
+// readYourWritesMiddleware pins a client's reads to the primary database for
+// a short window after it writes, so it does not read stale data from a
+// lagging replica. The window is tracked per client IP in Redis.
+func readYourWritesMiddleware(window time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !db.HasReplicas() {
+			c.Next()
+			return
+		}
+
+		ctx := c.Request.Context()
+		key := fmt.Sprintf("rw_sticky:%s", c.ClientIP())
+
+		pinned, err := cache.RDB.Exists(ctx, key).Result()
+		if err != nil {
+			// Fall back to the primary when stickiness cannot be checked
+			slog.WarnContext(ctx, "Error checking read-your-writes window", "error", err)
+		}
+		if err != nil || pinned > 0 {
+			c.Request = c.Request.WithContext(db.WithPrimary(ctx))
+		}
+
+		c.Next()
+
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			return
+		}
+		if c.Writer.Status() < 400 {
+			if err := cache.RDB.Set(ctx, key, 1, window).Err(); err != nil {
+				slog.WarnContext(ctx, "Error setting read-your-writes window", "error", err)
+			}
+		}
+	}
+}
+
+func setCache(ctx context.Context, key string, value string) {
+	err := cache.RDB.Set(ctx, key, value, 0).Err()
 	if err != nil {
-		log.Fatalf("Redis error: %v", err)
+		logging.Fatal("Redis error", "error", err)
 	}
 }
 
-func getCache(key string) (string, error) {
-	val, err := rdb.Get(context.Background(), key).Result()
+func getCache(ctx context.Context, key string) (string, error) {
+	val, err := cache.RDB.Get(ctx, key).Result()
 	if err != nil {
 		return "", err
 	}
@@ -114,29 +207,94 @@ func getCache(key string) (string, error) {
 // @externalDocs.description OpenAPI
 // @externalDocs.url https://swagger.io/resources/open-api/
 func main() {
-	// Load .env variables
-	err := godotenv.Load()
+	// Load configuration from defaults, config.yaml, .env and the environment
+	cfg, err := config.Load()
 	if err != nil {
-		log.Println("Error loading .env file")
+		logging.Fatal("Failed to load configuration", "error", err)
+	}
+
+	// "config print" dumps the effective configuration with secrets redacted
+	if len(os.Args) > 1 && os.Args[1] == "config" {
+		runConfigCommand(cfg, os.Args[2:])
+		return
+	}
+
+	if err := cfg.Validate(); err != nil {
+		logging.Fatal("Invalid configuration", "error", err)
+	}
+
+	// Structured logging; every request is tagged with its X-Request-ID
+	if err := logging.Setup(cfg.Log); err != nil {
+		logging.Fatal("Failed to set up logging", "error", err)
+	}
+
+	// OpenTelemetry tracing, exported to the OTLP collector when enabled
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing)
+	if err != nil {
+		logging.Fatal("Failed to set up tracing", "error", err)
 	}
 
 	// Initialize Redis
-	initRedis()
+	cache.InitRedis(cfg.Redis)
 
 	// Initialize database connection
-	db.InitDB()
+	db.InitDB(cfg.Database)
+	db.InitReplicas(cfg.Database)
+
+	// Storage for uploaded files
+	if err := blob.Init(cfg.Blob); err != nil {
+		logging.Fatal("Failed to set up blob storage", "error", err)
+	}
+
+	// Default language of the catalog and localized validation messages
+	if err := i18n.Init(cfg.I18n); err != nil {
+		logging.Fatal("Failed to set up localization", "error", err)
+	}
+
+	// Filters screening new reviews, and the moderators deciding held ones
+	moderation.Init(cfg.Moderation)
+
+	// Background delivery of queued webhook events
+	webhooks.Start(cfg.Webhooks)
 
-	// Setup Prometheus metrics
-	prometheus.MustRegister(requestCount)
+	// Relay of committed outbox events to Redis Streams
+	outbox.Start(cfg.Outbox)
 
-	// Initialize the router
-	r := gin.Default()
+	// Live events for the Server-Sent Events endpoints
+	events.Start(cfg.Outbox.Channel)
 
-	// Rate limiting middleware: 5 istek / 1 dakika
-	r.Use(rateLimitMiddleware(5, time.Minute))
+	// Setup Prometheus metrics, including connection pool statistics
+	prometheus.MustRegister(requestCount, health.DependencyUp, health.DependencyLatency)
+	sqlDB, err := db.DB.DB()
+	if err != nil {
+		logging.Fatal("Failed to access database pool", "error", err)
+	}
+	prometheus.MustRegister(collectors.NewDBStatsCollector(sqlDB, cfg.Database.Name))
+
+	// Dependency checks for the readiness probe
+	health.SetTimeout(cfg.Health.CheckTimeout)
+	health.Register("postgres", db.Ping)
+	health.Register("redis", cache.Ping)
+
+	// Initialize the router with structured access logs instead of gin's
+	// text logger
+	r := gin.New()
+	r.Use(logging.RequestIDMiddleware(), tracing.Middleware(), logging.AccessLog(), gin.Recovery())
+
+	// Languages the client accepts, for translated content and messages
+	r.Use(i18n.Middleware())
+
+	// Per-request deadline for database and Redis calls
+	r.Use(timeoutMiddleware(cfg.Server.RequestTimeout, routes.LongRunningRoutes...))
+
+	// Rate limiting middleware
+	r.Use(rateLimitMiddleware(cfg.RateLimit.Requests, cfg.RateLimit.Window))
+
+	// Keep reads on the primary right after a client writes
+	r.Use(readYourWritesMiddleware(cfg.Database.ReadYourWritesWindow))
 
 	// Set trusted proxies
-	r.SetTrustedProxies([]string{"127.0.0.1"})
+	r.SetTrustedProxies(cfg.Server.TrustedProxies)
 
 	// Serve Swagger documentation
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
@@ -145,22 +303,109 @@ func main() {
 	r.GET("/metrics", gin.WrapH(promhttp.Handler()))
 
 	// Setup routes
-	routes.SetupRoutes(r)
+	routes.SetupRoutes(r, cfg)
 
-	// Health check endpoint
-	r.GET("/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{"status": "healthy"})
-	})
+	// Liveness and readiness probes. /health is kept as an alias of /readyz.
+	r.GET("/livez", health.Livez)
+	r.GET("/readyz", health.Readyz)
+	r.GET("/health", health.Readyz)
+
+	// Start the gRPC server on its own port
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.GRPC.Port))
+	if err != nil {
+		logging.Fatal("Failed to listen on gRPC port", "port", cfg.GRPC.Port, "error", err)
+	}
+	grpcServer, grpcHealth := grpcserver.New()
+	go func() {
+		slog.Info("Starting gRPC server", "port", cfg.GRPC.Port)
+		if err := grpcServer.Serve(lis); err != nil {
+			logging.Fatal("Failed to start gRPC server", "error", err)
+		}
+	}()
 
-	// Get port from environment or use default
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	srv := &http.Server{
+		Addr:              fmt.Sprintf(":%d", cfg.Server.Port),
+		Handler:           handlers.WithResponseController(r),
+		ReadTimeout:       cfg.Server.ReadTimeout,
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+		WriteTimeout:      cfg.Server.WriteTimeout,
+		IdleTimeout:       cfg.Server.IdleTimeout,
 	}
+	// Event streams never finish on their own; end them when shutting down
+	srv.RegisterOnShutdown(events.Stop)
 
 	// Start the server
-	log.Printf("Starting server on port %s...", port)
-	if err := r.Run(":" + port); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+	go func() {
+		slog.Info("Starting server", "port", cfg.Server.Port)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logging.Fatal("Failed to start server", "error", err)
+		}
+	}()
+	health.SetReady(true)
+
+	// Wait for SIGINT or SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	<-ctx.Done()
+	stop()
+
+	shutdown(cfg.Shutdown, srv, grpcServer, grpcHealth, shutdownTracing)
+}
+
+// runConfigCommand handles "config print", printing the effective redacted
+// configuration and exiting non-zero if it does not validate.
+func runConfigCommand(cfg config.Config, args []string) {
+	if len(args) != 1 || args[0] != "print" {
+		fmt.Fprintln(os.Stderr, "usage: main config print")
+		os.Exit(2)
+	}
+	if err := cfg.Print(os.Stdout); err != nil {
+		logging.Fatal("Failed to print configuration", "error", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid configuration:\n%v\n", err)
+		os.Exit(1)
+	}
+}
+
+// shutdown fails readiness checks, gives load balancers the drain period to
+// notice, then stops accepting connections and waits up to the shutdown
+// timeout for in-flight requests before closing Redis and the database pool
+// and flushing pending trace spans.
+func shutdown(cfg config.ShutdownConfig, srv *http.Server, grpcServer *grpc.Server, grpcHealth *grpchealth.Server, shutdownTracing func(context.Context) error) {
+	slog.Info("Shutting down, failing readiness checks")
+	health.SetReady(false)
+	grpcHealth.Shutdown()
+	time.Sleep(cfg.DrainPeriod)
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Timeout)
+	defer cancel()
+
+	if err := srv.Shutdown(ctx); err != nil {
+		slog.Error("HTTP server shutdown", "error", err)
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		grpcServer.Stop()
+	}
+
+	webhooks.Stop()
+	outbox.Stop()
+	if err := cache.Close(); err != nil {
+		slog.Error("Redis close", "error", err)
+	}
+	if err := db.Close(); err != nil {
+		slog.Error("Database close", "error", err)
+	}
+	if err := shutdownTracing(ctx); err != nil {
+		slog.Error("Tracing shutdown", "error", err)
 	}
+	slog.Info("Server stopped")
 }